@@ -0,0 +1,7 @@
+package cascade
+
+import "github.com/indeedhat/dotenv"
+
+func init() {
+	_, _ = dotenv.LoadCascade(dotenv.WithOverride())
+}
@@ -0,0 +1,80 @@
+package dotenv
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WithCascade adds the environment specific file cascade to the list of files to be loaded.
+//
+// For an environment name of "production" the files are loaded in order of precedence:
+// - .env.production.local
+// - .env.local
+// - .env.production
+// - .env
+//
+// .env.local is skipped for the "test" environment so that tests produce the same results for
+// everyone, all of the cascade files are optional.
+// The environment name is taken from WithEnvName or APP_ENV falling back to GO_ENV, if none is set
+// then only .env.local and .env are loaded. The load will fail if the name contains a path
+// separator or .. so that it cannot reach files outside of the directory.
+//
+// When used with WithOverride the files are loaded in reverse so the precedence stays the same
+func WithCascade() Option {
+	return func(o *options) {
+		o.cascade = true
+	}
+}
+
+// WithEnvName sets the environment name used by WithCascade
+func WithEnvName(name string) Option {
+	return func(o *options) {
+		o.envName = name
+	}
+}
+
+// LoadCascade loads the environment specific file cascade into the environment, see WithCascade
+// for the files that make up the cascade.
+//
+// The paths of the files that were loaded are returned in the order they were loaded
+func LoadCascade(opts ...Option) ([]string, error) {
+	var result LoadResult
+
+	err := LoadWith(append(opts, WithCascade(), WithResult(&result))...)
+
+	return result.Files, err
+}
+
+// cascadeFiles returns the files that make up the cascade in the order they should be loaded
+func (o *options) cascadeFiles() ([]envFile, error) {
+	name := o.envName
+	if name == "" {
+		name = String("APP_ENV").GetFrom(o.resolve, String("GO_ENV").GetFrom(o.resolve))
+	}
+
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("invalid environment name %q", name)
+	}
+
+	var files []envFile
+	if name != "" {
+		files = append(files, envFile{path: ".env." + name + ".local", optional: true})
+	}
+
+	if name != "test" {
+		files = append(files, envFile{path: ".env.local", optional: true})
+	}
+
+	if name != "" {
+		files = append(files, envFile{path: ".env." + name, optional: true})
+	}
+
+	files = append(files, envFile{path: ".env", optional: true})
+
+	if o.overwrite {
+		slices.Reverse(files)
+	}
+
+	return files, nil
+}
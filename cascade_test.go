@@ -0,0 +1,175 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+var cascadeFS = fstest.MapFS{
+	".env":                  &fstest.MapFile{Data: []byte("BASE=env\nLOCAL=env\nNAMED=env\nNAMED_LOCAL=env")},
+	".env.local":            &fstest.MapFile{Data: []byte("LOCAL=local\nNAMED=local\nNAMED_LOCAL=local")},
+	".env.production":       &fstest.MapFile{Data: []byte("NAMED=production\nNAMED_LOCAL=production")},
+	".env.production.local": &fstest.MapFile{Data: []byte("NAMED_LOCAL=production.local")},
+	".env.test":             &fstest.MapFile{Data: []byte("NAMED=test\nNAMED_LOCAL=test")},
+}
+
+var cascadeTestCases = []struct {
+	name          string
+	env           map[string]string
+	opts          []Option
+	expectedFiles []string
+	expected      map[string]string
+}{
+	{
+		name:          "production",
+		opts:          []Option{WithEnvName("production")},
+		expectedFiles: []string{".env.production.local", ".env.local", ".env.production", ".env"},
+		expected: map[string]string{
+			"BASE":        "env",
+			"LOCAL":       "local",
+			"NAMED":       "local",
+			"NAMED_LOCAL": "production.local",
+		},
+	},
+	{
+		name:          "production override",
+		opts:          []Option{WithEnvName("production"), WithOverride()},
+		expectedFiles: []string{".env", ".env.production", ".env.local", ".env.production.local"},
+		expected: map[string]string{
+			"BASE":        "env",
+			"LOCAL":       "local",
+			"NAMED":       "local",
+			"NAMED_LOCAL": "production.local",
+		},
+	},
+	{
+		name:          "test skips local",
+		opts:          []Option{WithEnvName("test")},
+		expectedFiles: []string{".env.test", ".env"},
+		expected: map[string]string{
+			"BASE":        "env",
+			"LOCAL":       "env",
+			"NAMED":       "test",
+			"NAMED_LOCAL": "test",
+		},
+	},
+	{
+		name:          "no env name",
+		expectedFiles: []string{".env.local", ".env"},
+		expected: map[string]string{
+			"BASE":        "env",
+			"LOCAL":       "local",
+			"NAMED":       "local",
+			"NAMED_LOCAL": "local",
+		},
+	},
+	{
+		name:          "APP_ENV",
+		env:           map[string]string{"APP_ENV": "test", "GO_ENV": "production"},
+		expectedFiles: []string{".env.test", ".env"},
+		expected: map[string]string{
+			"APP_ENV":     "test",
+			"GO_ENV":      "production",
+			"BASE":        "env",
+			"LOCAL":       "env",
+			"NAMED":       "test",
+			"NAMED_LOCAL": "test",
+		},
+	},
+	{
+		name:          "GO_ENV",
+		env:           map[string]string{"GO_ENV": "test"},
+		expectedFiles: []string{".env.test", ".env"},
+		expected: map[string]string{
+			"GO_ENV":      "test",
+			"BASE":        "env",
+			"LOCAL":       "env",
+			"NAMED":       "test",
+			"NAMED_LOCAL": "test",
+		},
+	},
+	{
+		name:          "missing env files",
+		opts:          []Option{WithEnvName("staging")},
+		expectedFiles: []string{".env.local", ".env"},
+		expected: map[string]string{
+			"BASE":        "env",
+			"LOCAL":       "local",
+			"NAMED":       "local",
+			"NAMED_LOCAL": "local",
+		},
+	},
+}
+
+func TestLoadCascade(t *testing.T) {
+	for _, tc := range cascadeTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := NewMapEnv(tc.env)
+
+			files, err := LoadCascade(append(tc.opts, WithFS(cascadeFS), WithEnvironment(env))...)
+			require.Nil(t, err)
+
+			require.Equal(t, tc.expectedFiles, files)
+			require.Equal(t, tc.expected, env.Map())
+		})
+	}
+}
+
+func TestLoadCascadeWithFiles(t *testing.T) {
+	env := NewMapEnv(nil)
+
+	files, err := LoadCascade(
+		WithFS(cascadeFS),
+		WithEnvironment(env),
+		WithEnvName("test"),
+		WithFiles(".env.production"),
+	)
+	require.Nil(t, err)
+
+	require.Equal(t, []string{".env.production", ".env.test", ".env"}, files)
+	require.Equal(t, map[string]string{
+		"BASE":        "env",
+		"LOCAL":       "env",
+		"NAMED":       "production",
+		"NAMED_LOCAL": "production",
+	}, env.Map())
+}
+
+func TestReadWithCascade(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("APP_ENV", "production")
+
+	envars, err := ReadWith(WithCascade(), WithFS(cascadeFS))
+	require.Nil(t, err)
+
+	require.Equal(t, map[string]string{
+		"BASE":        "env",
+		"LOCAL":       "local",
+		"NAMED":       "local",
+		"NAMED_LOCAL": "production.local",
+	}, envars)
+}
+
+func TestLoadCascadeInvalidEnvName(t *testing.T) {
+	for _, name := range []string{"../../x", "prod/x", `prod\x`, "..", "x..y"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			env := NewMapEnv(map[string]string{"APP_ENV": name})
+
+			files, err := LoadCascade(WithFS(cascadeFS), WithEnvironment(env))
+			require.EqualError(t, err, fmt.Sprintf("invalid environment name %q", name))
+			require.Empty(t, files)
+			require.Equal(t, map[string]string{"APP_ENV": name}, env.Map())
+
+			_, err = LoadCascade(WithFS(cascadeFS), WithEnvironment(NewMapEnv(nil)), WithEnvName(name))
+			require.Error(t, err)
+		})
+	}
+}
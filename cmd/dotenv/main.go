@@ -0,0 +1,93 @@
+// Command dotenv explains where the values of environment variables loaded from .env files come
+// from
+//
+// Usage:
+//
+//	dotenv explain [flags] KEY...
+//
+// The files are loaded into the environment of the command in the same way the library would load
+// them, the definitions of each key are then printed with the winning definition marked with a *
+//
+// The values of sensitive keys matching dotenv.DefaultRedactedKeys are always hidden
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/indeedhat/dotenv"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// list collects the values of a repeatable flag
+type list []string
+
+func (l *list) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *list) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "explain" {
+		fmt.Fprintln(stderr, "usage: dotenv explain [flags] KEY...")
+		return 2
+	}
+
+	var paths, redact list
+
+	flags := flag.NewFlagSet("explain", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Var(&paths, "f", "`file` to load, can be repeated (default .env)")
+	override := flags.Bool("override", false, "replace existing variables, the same as Overload")
+	strict := flags.Bool("strict", false, "fail on invalid syntax")
+	cascade := flags.Bool("cascade", false, "load the environment specific file cascade")
+	envName := flags.String("env", "", "environment `name` for the cascade (default $APP_ENV or $GO_ENV)")
+	flags.Var(&redact, "redact", "key `pattern` to hide the value of in addition to the defaults, can be repeated")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: dotenv explain [flags] KEY...")
+		return 2
+	}
+
+	opts := []dotenv.Option{dotenv.WithFiles(paths...), dotenv.WithProvenance()}
+	if *override {
+		opts = append(opts, dotenv.WithOverride())
+	}
+	if *strict {
+		opts = append(opts, dotenv.WithStrict())
+	}
+	if *cascade {
+		opts = append(opts, dotenv.WithCascade(), dotenv.WithEnvName(*envName))
+	}
+	if len(redact) > 0 {
+		opts = append(opts, dotenv.WithRedaction(redact...))
+	}
+
+	// NB: only the definitions from this load should be explained
+	dotenv.ResetProvenance()
+
+	if err := dotenv.LoadWith(opts...); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	for _, key := range flags.Args() {
+		fmt.Fprintln(stdout, dotenv.Explain(key))
+	}
+
+	return 0
+}
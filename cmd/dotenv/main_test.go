@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunExplain(t *testing.T) {
+	os.Clearenv()
+	t.Chdir(t.TempDir())
+
+	require.Nil(t, os.WriteFile(".env", []byte("HOST=localhost\nAPI_URL=\"${HOST}/v1\""), 0644))
+	require.Nil(t, os.WriteFile(".env.local", []byte("API_URL=\"${HOST}/v2\""), 0644))
+
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"explain", "-f", ".env.local", "-f", ".env", "API_URL", "MISSING"}, &stdout, &stderr)
+	require.Equal(t, 0, code)
+	require.Empty(t, stderr.String())
+	require.Equal(t, `API_URL
+* .env.local:1: set "${HOST}/v2" => "/v2"
+  .env:2: skipped "${HOST}/v1" => "localhost/v1"
+MISSING: not loaded from any file
+`, stdout.String())
+}
+
+func TestRunRedact(t *testing.T) {
+	os.Clearenv()
+	t.Chdir(t.TempDir())
+
+	require.Nil(t, os.WriteFile(".env", []byte("HOST=localhost\nDB_PASSWORD=hunter2"), 0644))
+
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"explain", "-redact", "HOST", "HOST", "DB_PASSWORD"}, &stdout, &stderr)
+	require.Equal(t, 0, code)
+	require.Empty(t, stderr.String())
+	require.Equal(t, `HOST
+* .env:1: set "[REDACTED]" => "[REDACTED]"
+DB_PASSWORD
+* .env:2: set "[REDACTED]" => "[REDACTED]"
+`, stdout.String())
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	require.Equal(t, 2, run(nil, &stdout, &stderr))
+	require.Equal(t, 2, run([]string{"explain"}, &stdout, &stderr))
+	require.Equal(t, 2, run([]string{"list"}, &stdout, &stderr))
+	require.Empty(t, stdout.String())
+}
+
+func TestRunLoadError(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var stdout, stderr bytes.Buffer
+
+	require.Equal(t, 1, run([]string{"explain", "KEY"}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), ".env")
+}
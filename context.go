@@ -0,0 +1,25 @@
+package dotenv
+
+import "context"
+
+// envContextKey is the context key for the Environment attached by WithEnv
+type envContextKey struct{}
+
+// WithEnv returns a copy of ctx with env attached, the Ctx methods of the helper types will read
+// from env when given the returned context
+//
+// Combined with a LayeredEnv over OSEnv this allows request scoped overrides of the process
+// environment
+func WithEnv(ctx context.Context, env Environment) context.Context {
+	return context.WithValue(ctx, envContextKey{}, env)
+}
+
+// EnvFrom returns the Environment attached to ctx by WithEnv, if none is attached then the
+// os.environment is returned
+func EnvFrom(ctx context.Context) Environment {
+	if env, ok := ctx.Value(envContextKey{}).(Environment); ok && env != nil {
+		return env
+	}
+
+	return OSEnv{}
+}
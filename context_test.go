@@ -0,0 +1,38 @@
+package dotenv
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvFrom(t *testing.T) {
+	env := NewMapEnv(nil)
+
+	require.Equal(t, OSEnv{}, EnvFrom(context.Background()))
+	require.Equal(t, env, EnvFrom(WithEnv(context.Background(), env)))
+	require.Equal(t, OSEnv{}, EnvFrom(WithEnv(context.Background(), nil)))
+}
+
+func TestWithEnvFallback(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("TENANT", "default")
+	os.Setenv("SHARED", "process")
+
+	const (
+		envTenant String = "TENANT"
+		envShared String = "SHARED"
+	)
+
+	tenant := NewLayeredEnv(NewMapEnv(map[string]string{"TENANT": "acme"}), OSEnv{})
+	ctx := WithEnv(context.Background(), tenant)
+
+	require.Equal(t, "acme", envTenant.GetCtx(ctx))
+	require.Equal(t, "process", envShared.GetCtx(ctx))
+
+	// contexts without an attached environment fall back to the process environment
+	require.Equal(t, "default", envTenant.GetCtx(context.Background()))
+	require.Equal(t, "default", envTenant.LookupCtx(context.Background(), "fallback"))
+}
@@ -1,127 +1,277 @@
 package dotenv
 
-import "os"
+import (
+	"io"
+	"io/fs"
+	"os"
+)
 
 // Load loads the provided list of .env files into the os.environment.
 // If no files are provided it will default to loading .env from the current working directory.
+// Paths ending with a ? (.env.local?) are optional and will be skipped if they do not exist.
 //
 // Looad operoations will not replace any existing variables already in the environment.
 func Load(filepaths ...string) error {
-	for _, filepath := range pathFallback(filepaths) {
-		p, err := ParseFile(filepath)
-		if err != nil {
-			return err
-		}
-
-		assignEnvars(p.Parse(), false)
-	}
-
-	return nil
+	return LoadWith(WithFiles(filepaths...))
 }
 
 // LoadStrict loads the provided list of .env files into the os.environment.
 // If no files are provided it will default to loading .env from the current working directory.
+// Paths ending with a ? (.env.local?) are optional and will be skipped if they do not exist.
 //
 // Looad operoations will not replace any existing variables already in the environment.
 //
-// Strict operations will break at the first .env file that contains invalid syntax, all previous
-// files in the list will still be loaded into the environment but any files that appear in the list
-// after the first invalid file will be skipped
+// Strict operations will stop loading at the first .env file that contains invalid syntax, all
+// previous files in the list will still be loaded into the environment but any files that appear
+// in the list after the first invalid file will be skipped.
+// Skipped files are still parsed so that the returned error can report every syntax error found,
+// it joins a *SyntaxError for each invalid line, each containing the path of its file.
+// If a required file cannot be read then nothing after it is parsed, its error is joined after the
+// syntax errors already found so errors.Is(err, fs.ErrNotExist) still reports a missing file.
+// Missing optional files are skipped and never cause an error.
 func LoadStrict(filepaths ...string) error {
-	for _, filepath := range pathFallback(filepaths) {
-		p, err := ParseFile(filepath)
-		if err != nil {
-			return err
-		}
-
-		pairs, err := p.ParseStrict()
-		if err != nil {
-			return err
-		}
-
-		assignEnvars(pairs, false)
-	}
-
-	return nil
+	return LoadWith(WithFiles(filepaths...), WithStrict())
 }
 
 // Overload loads the provided list of .env files into the os.environment.
 // If no files are provided it will default to loading .env from the current working directory.
+// Paths ending with a ? (.env.local?) are optional and will be skipped if they do not exist.
 //
 // Unlike with the Load operation any existing environment variables will be overloaded with the
 // present in the provided env files.
 func Overload(filepaths ...string) error {
-	for _, filepath := range pathFallback(filepaths) {
-		p, err := ParseFile(filepath)
-		if err != nil {
-			return err
-		}
-
-		assignEnvars(p.Parse(), true)
-	}
-
-	return nil
+	return LoadWith(WithFiles(filepaths...), WithOverride())
 }
 
 // OverloadStrict loads the provided list of .env files into the os.environment.
 // If no files are provided it will default to loading .env from the current working directory.
+// Paths ending with a ? (.env.local?) are optional and will be skipped if they do not exist.
 //
 // Unlike with the Load operation any existing environment variables will be overloaded with the
 // present in the provided env files.
 //
-// Strict operations will break at the first .env file that contains invalid syntax, all previous
-// files in the list will still be loaded into the environment but any files that appear in the list
-// after the first invalid file will be skipped
+// Strict operations will stop loading at the first .env file that contains invalid syntax, all
+// previous files in the list will still be loaded into the environment but any files that appear
+// in the list after the first invalid file will be skipped.
+// Skipped files are still parsed so that the returned error can report every syntax error found,
+// it joins a *SyntaxError for each invalid line, each containing the path of its file.
+// If a required file cannot be read then nothing after it is parsed, its error is joined after the
+// syntax errors already found so errors.Is(err, fs.ErrNotExist) still reports a missing file.
+// Missing optional files are skipped and never cause an error.
 func OverloadStrict(filepaths ...string) error {
-	for _, filepath := range pathFallback(filepaths) {
-		p, err := ParseFile(filepath)
-		if err != nil {
-			return err
-		}
-
-		pairs, err := p.ParseStrict()
-		if err != nil {
-			return err
-		}
-
-		assignEnvars(pairs, true)
+	return LoadWith(WithFiles(filepaths...), WithOverride(), WithStrict())
+}
+
+// Read reads the provided list of .env files into a map without touching the os.environment.
+// If no files are provided it will default to reading .env from the current working directory.
+//
+// Files are handled with the same precedence as Load, the first file to define a variable wins.
+// ${VAR} expansion will resolve variables from the files first falling back to the
+// os.environment, existing variables in the os.environment do not prevent a variable being read.
+func Read(filepaths ...string) (map[string]string, error) {
+	return ReadWith(WithFiles(filepaths...))
+}
+
+// ReadStrict reads the provided list of .env files into a map without touching the os.environment.
+// If no files are provided it will default to reading .env from the current working directory.
+//
+// This works the same way as Read but will fail if any of the files contain invalid syntax, the
+// returned error joins a *SyntaxError for every invalid line across all of the files
+func ReadStrict(filepaths ...string) (map[string]string, error) {
+	return ReadWith(WithFiles(filepaths...), WithStrict())
+}
+
+// ReadWith reads .env files into a map as configured by the provided options without touching the
+// os.environment.
+//
+// This works the same way as Read, any environment set by WithEnvironment will be ignored
+func ReadWith(opts ...Option) (map[string]string, error) {
+	env := NewMapEnv(nil)
+
+	o := newOptions(append(opts, WithEnvironment(env))...)
+	o.resolve = NewLayeredEnv(env, OSEnv{})
+
+	if err := o.load(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return env.Map(), nil
+}
+
+// LoadEnv loads the provided list of .env files into env.
+// If no files are provided it will default to loading .env from the current working directory.
+//
+// This works the same way as Load but allows the envars to be loaded into any Environment,
+// ${VAR} expansion will resolve variables from env.
+func LoadEnv(env Environment, filepaths ...string) error {
+	return LoadWith(WithEnvironment(env), WithFiles(filepaths...))
+}
+
+// LoadStrictEnv loads the provided list of .env files into env.
+// If no files are provided it will default to loading .env from the current working directory.
+//
+// This works the same way as LoadStrict but allows the envars to be loaded into any Environment,
+// ${VAR} expansion will resolve variables from env.
+func LoadStrictEnv(env Environment, filepaths ...string) error {
+	return LoadWith(WithEnvironment(env), WithFiles(filepaths...), WithStrict())
+}
+
+// OverloadEnv loads the provided list of .env files into env.
+// If no files are provided it will default to loading .env from the current working directory.
+//
+// This works the same way as Overload but allows the envars to be loaded into any Environment,
+// ${VAR} expansion will resolve variables from env.
+func OverloadEnv(env Environment, filepaths ...string) error {
+	return LoadWith(WithEnvironment(env), WithFiles(filepaths...), WithOverride())
+}
+
+// OverloadStrictEnv loads the provided list of .env files into env.
+// If no files are provided it will default to loading .env from the current working directory.
+//
+// This works the same way as OverloadStrict but allows the envars to be loaded into any
+// Environment, ${VAR} expansion will resolve variables from env.
+func OverloadStrictEnv(env Environment, filepaths ...string) error {
+	return LoadWith(WithEnvironment(env), WithFiles(filepaths...), WithOverride(), WithStrict())
+}
+
+// LoadFS loads the provided list of .env files from fsys into the os.environment.
+// If no files are provided it will default to loading .env from the root of fsys.
+//
+// This works the same way as Load but allows files to be loaded from any fs.FS such as an
+// embed.FS, paths must follow the rules of fs.ValidPath.
+func LoadFS(fsys fs.FS, filepaths ...string) error {
+	return LoadWith(WithFS(fsys), WithFiles(filepaths...))
+}
+
+// LoadStrictFS loads the provided list of .env files from fsys into the os.environment.
+// If no files are provided it will default to loading .env from the root of fsys.
+//
+// This works the same way as LoadStrict but allows files to be loaded from any fs.FS such as an
+// embed.FS, paths must follow the rules of fs.ValidPath.
+func LoadStrictFS(fsys fs.FS, filepaths ...string) error {
+	return LoadWith(WithFS(fsys), WithFiles(filepaths...), WithStrict())
+}
+
+// OverloadFS loads the provided list of .env files from fsys into the os.environment.
+// If no files are provided it will default to loading .env from the root of fsys.
+//
+// This works the same way as Overload but allows files to be loaded from any fs.FS such as an
+// embed.FS, paths must follow the rules of fs.ValidPath.
+func OverloadFS(fsys fs.FS, filepaths ...string) error {
+	return LoadWith(WithFS(fsys), WithFiles(filepaths...), WithOverride())
+}
+
+// OverloadStrictFS loads the provided list of .env files from fsys into the os.environment.
+// If no files are provided it will default to loading .env from the root of fsys.
+//
+// This works the same way as OverloadStrict but allows files to be loaded from any fs.FS such as
+// an embed.FS, paths must follow the rules of fs.ValidPath.
+func OverloadStrictFS(fsys fs.FS, filepaths ...string) error {
+	return LoadWith(WithFS(fsys), WithFiles(filepaths...), WithOverride(), WithStrict())
+}
+
+// LoadReader loads the env data read from r into the os.environment.
+//
+// Load operations will not replace any existing variables already in the environment.
+//
+// The optional name will be used in place of a file path in any errors produced while parsing
+func LoadReader(r io.Reader, name ...string) error {
+	return LoadWith(WithReader(r, name...))
+}
+
+// LoadStrictReader loads the env data read from r into the os.environment.
+//
+// Load operations will not replace any existing variables already in the environment.
+//
+// Strict operations will not load anything if the data contains invalid syntax, the returned error
+// joins a *SyntaxError for each invalid line
+func LoadStrictReader(r io.Reader, name ...string) error {
+	return LoadWith(WithReader(r, name...), WithStrict())
+}
+
+// OverloadReader loads the env data read from r into the os.environment.
+//
+// Unlike with the LoadReader operation any existing environment variables will be overloaded with
+// the values present in the data.
+//
+// The optional name will be used in place of a file path in any errors produced while parsing
+func OverloadReader(r io.Reader, name ...string) error {
+	return LoadWith(WithReader(r, name...), WithOverride())
+}
+
+// OverloadStrictReader loads the env data read from r into the os.environment.
+//
+// Unlike with the LoadStrictReader operation any existing environment variables will be overloaded
+// with the values present in the data.
+//
+// Strict operations will not load anything if the data contains invalid syntax, the returned error
+// joins a *SyntaxError for each invalid line
+func OverloadStrictReader(r io.Reader, name ...string) error {
+	return LoadWith(WithReader(r, name...), WithOverride(), WithStrict())
 }
 
 // ParseFile returns the underlying Parser instance representing the provided env file
 //
 // This will not load anything into the environment but allow you to handle the found values manually
 func ParseFile(filepath string) (*Parser, error) {
-	data, err := os.ReadFile(filepath)
+	return parseFile(os.ReadFile, filepath)
+}
+
+// ParseFileFS returns the underlying Parser instance representing the env file at filepath in fsys
+//
+// This will not load anything into the environment but allow you to handle the found values manually
+func ParseFileFS(fsys fs.FS, filepath string) (*Parser, error) {
+	return parseFile(readFileFS(fsys), filepath)
+}
+
+// ParseReader returns the underlying Parser instance representing the env data read from r
+//
+// The optional name will be used in place of a file path in any errors or warnings produced by the
+// parser
+func ParseReader(r io.Reader, name ...string) (*Parser, error) {
+	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
-	return newParser(newLexer(string(data))), nil
+	return ParseBytes(data, name...), nil
 }
 
-func pathFallback(filepaths []string) []string {
-	if len(filepaths) == 0 {
-		return []string{".env"}
+// ParseBytes returns the underlying Parser instance representing the provided env data
+//
+// The optional name will be used in place of a file path in any errors or warnings produced by the
+// parser
+func ParseBytes(data []byte, name ...string) *Parser {
+	return ParseString(string(data), name...)
+}
+
+// ParseString returns the underlying Parser instance representing the provided env data
+//
+// The optional name will be used in place of a file path in any errors or warnings produced by the
+// parser
+func ParseString(data string, name ...string) *Parser {
+	p := newParser(newLexer(data))
+	if len(name) > 0 {
+		p.name = name[0]
 	}
 
-	return filepaths
+	return p
 }
 
-func assignEnvars(pairs []ParseEntry, overwrite bool) {
-	for _, v := range pairs {
-		if !overwrite {
-			if _, ok := os.LookupEnv(v.Key); ok {
-				continue
-			}
-		}
+// readFileFunc reads the full contents of the file at the given path
+type readFileFunc func(string) ([]byte, error)
+
+func readFileFS(fsys fs.FS) readFileFunc {
+	return func(filepath string) ([]byte, error) {
+		return fs.ReadFile(fsys, filepath)
+	}
+}
 
-		if !v.Raw && v.Value != "" {
-			os.Setenv(v.Key, Expand(v.Value, os.Getenv))
-		} else {
-			os.Setenv(v.Key, v.Value)
-		}
+func parseFile(readFile readFileFunc, filepath string) (*Parser, error) {
+	data, err := readFile(filepath)
+	if err != nil {
+		return nil, err
 	}
+
+	return ParseBytes(data, filepath), nil
 }
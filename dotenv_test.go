@@ -2,8 +2,12 @@ package dotenv
 
 import (
 	"errors"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/require"
 )
@@ -76,6 +80,54 @@ var loadTestCases = []struct {
 			"REPLACE_FROM_BROKEN=",
 		},
 	},
+	{
+		"escapes",
+		[]string{"fixtures/escapes.env"},
+		[]string{
+			"NEWLINE=line1\nline2",
+			"TAB=col1\tcol2",
+			"QUOTES=say \"hi\" and 'bye'",
+			"BACKSLASH=C:\\path",
+			"DOLLAR=${VALUE}",
+			"UNICODE=café 😀",
+			"HEX=AB",
+			"RAW=line1\\nline2",
+			"RAW_QUOTE=it's",
+		},
+	},
+	{
+		"multi line",
+		[]string{"fixtures/multiline.env"},
+		[]string{
+			"TRIPLE_DOUBLE=first \n\tsecond\tline",
+			"TRIPLE_SINGLE=raw ${VALUE}\\n",
+			"INLINE_TRIPLE=one \"quoted\" line",
+			"HEREDOC=SELECT *\n  FROM table;",
+			"HEREDOC_RAW=${VALUE}",
+			"HEREDOC_INDENTED=-----BEGIN KEY-----\n  abc\n-----END KEY-----",
+			"AFTER=value",
+		},
+	},
+	{
+		"continuation",
+		[]string{"fixtures/continuation.env"},
+		[]string{
+			"HOSTS=one.example.com,two.example.com,three.example.com",
+			"CRLF=first second",
+			"QUOTED=quoted continuation",
+			"LAST=no trailing newline",
+		},
+	},
+	{
+		"backtick",
+		[]string{"fixtures/backtick.env"},
+		[]string{
+			"VALUE=inserted",
+			"BACKTICK=it's \"mixed\"",
+			"BACKTICK_EXPAND=inserted\\n",
+			"BACKTICK_ESCAPED=${VALUE} ` tick",
+		},
+	},
 }
 
 func TestLoad(t *testing.T) {
@@ -116,7 +168,26 @@ var loadStrictTestCases = []struct {
 		"broken",
 		[]string{"fixtures/broken.env"},
 		[]string{},
-		errors.New("Unexpected token IDENT value=some line=0 pos=6"),
+		errors.Join(
+			&SyntaxError{
+				File:     "fixtures/broken.env",
+				Line:     1,
+				Column:   6,
+				Token:    tknIdentifier,
+				Literal:  "some",
+				Expected: []string{tknEquals},
+				Source:   "just some words",
+			},
+			&SyntaxError{
+				File:     "fixtures/broken.env",
+				Line:     5,
+				Column:   1,
+				Token:    tknEquals,
+				Literal:  "=",
+				Expected: []string{tknIdentifier, tknExport, tknComment, tknEOL},
+				Source:   "='single quote'",
+			},
+		),
 	},
 	{
 		"multi file",
@@ -131,7 +202,60 @@ var loadStrictTestCases = []struct {
 			"WITH_COMMENT=some data",
 			"MULTI_LINE=this\none has multiple\nlines",
 		},
-		errors.New("Unexpected token IDENT value=some line=0 pos=6"),
+		errors.Join(
+			&SyntaxError{
+				File:     "fixtures/broken.env",
+				Line:     1,
+				Column:   6,
+				Token:    tknIdentifier,
+				Literal:  "some",
+				Expected: []string{tknEquals},
+				Source:   "just some words",
+			},
+			&SyntaxError{
+				File:     "fixtures/broken.env",
+				Line:     5,
+				Column:   1,
+				Token:    tknEquals,
+				Literal:  "=",
+				Expected: []string{tknIdentifier, tknExport, tknComment, tknEOL},
+				Source:   "='single quote'",
+			},
+		),
+	},
+	{
+		"errors in later files",
+		[]string{"fixtures/broken.env", "fixtures/invalid_escape.env"},
+		[]string{},
+		errors.Join(
+			&SyntaxError{
+				File:     "fixtures/broken.env",
+				Line:     1,
+				Column:   6,
+				Token:    tknIdentifier,
+				Literal:  "some",
+				Expected: []string{tknEquals},
+				Source:   "just some words",
+			},
+			&SyntaxError{
+				File:     "fixtures/broken.env",
+				Line:     5,
+				Column:   1,
+				Token:    tknEquals,
+				Literal:  "=",
+				Expected: []string{tknIdentifier, tknExport, tknComment, tknEOL},
+				Source:   "='single quote'",
+			},
+			&SyntaxError{
+				File:    "fixtures/invalid_escape.env",
+				Line:    2,
+				Column:  14,
+				Token:   tknIllegal,
+				Literal: "\\q",
+				Reason:  "invalid escape sequence",
+				Source:  `INVALID="bad \q escape"`,
+			},
+		),
 	},
 }
 
@@ -219,6 +343,26 @@ var overloadTestCases = []struct {
 	},
 }
 
+func TestLoadStrictMissingAfterInvalid(t *testing.T) {
+	os.Clearenv()
+
+	err := LoadStrict("fixtures/broken.env", "fixtures/missing.env", "fixtures/invalid_escape.env")
+
+	var syntaxErr *SyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	// NB: the syntax errors of broken.env come first, invalid_escape.env is never parsed
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	require.Len(t, errs, 3)
+	require.ErrorIs(t, errs[2], fs.ErrNotExist)
+	require.Empty(t, os.Environ())
+
+	err = LoadStrict("fixtures/broken.env", "fixtures/missing.env?")
+	require.ErrorAs(t, err, &syntaxErr)
+	require.NotErrorIs(t, err, fs.ErrNotExist)
+}
+
 func TestOverload(t *testing.T) {
 	for _, tc := range overloadTestCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -257,7 +401,26 @@ var overloadStrictTestCases = []struct {
 		"broken",
 		[]string{"fixtures/broken.env"},
 		[]string{},
-		errors.New("Unexpected token IDENT value=some line=0 pos=6"),
+		errors.Join(
+			&SyntaxError{
+				File:     "fixtures/broken.env",
+				Line:     1,
+				Column:   6,
+				Token:    tknIdentifier,
+				Literal:  "some",
+				Expected: []string{tknEquals},
+				Source:   "just some words",
+			},
+			&SyntaxError{
+				File:     "fixtures/broken.env",
+				Line:     5,
+				Column:   1,
+				Token:    tknEquals,
+				Literal:  "=",
+				Expected: []string{tknIdentifier, tknExport, tknComment, tknEOL},
+				Source:   "='single quote'",
+			},
+		),
 	},
 	{
 		"multi file",
@@ -272,7 +435,26 @@ var overloadStrictTestCases = []struct {
 			"WITH_COMMENT=some data",
 			"MULTI_LINE=this\none has multiple\nlines",
 		},
-		errors.New("Unexpected token IDENT value=some line=0 pos=6"),
+		errors.Join(
+			&SyntaxError{
+				File:     "fixtures/broken.env",
+				Line:     1,
+				Column:   6,
+				Token:    tknIdentifier,
+				Literal:  "some",
+				Expected: []string{tknEquals},
+				Source:   "just some words",
+			},
+			&SyntaxError{
+				File:     "fixtures/broken.env",
+				Line:     5,
+				Column:   1,
+				Token:    tknEquals,
+				Literal:  "=",
+				Expected: []string{tknIdentifier, tknExport, tknComment, tknEOL},
+				Source:   "='single quote'",
+			},
+		),
 	},
 }
 
@@ -301,3 +483,325 @@ func TestParseFile(t *testing.T) {
 		})
 	}
 }
+
+func TestParseString(t *testing.T) {
+	p := ParseString("FIRST=1\nSECOND=\"two\"", "inline")
+
+	pairs, err := p.ParseStrict()
+	require.Nil(t, err)
+	require.Equal(t, []ParseEntry{
+		{Key: "FIRST", Value: "1", File: "inline", Line: 1, Column: 1, HasValue: true},
+		{Key: "SECOND", Value: "two", File: "inline", Line: 2, Column: 1, Quote: QuoteDouble, HasValue: true},
+	}, pairs)
+}
+
+func TestParseReader(t *testing.T) {
+	p, err := ParseReader(strings.NewReader("VALID=1\njust some words\n"), "http body")
+	require.Nil(t, err)
+
+	_, err = p.ParseStrict()
+
+	var syntaxErr *SyntaxError
+	require.True(t, errors.As(err, &syntaxErr))
+	require.Equal(t, "http body:2:6: unexpected IDENT \"some\", expected EQUALS", err.Error())
+}
+
+func TestParseBytesWithoutName(t *testing.T) {
+	_, err := ParseBytes([]byte("=value")).ParseStrict()
+	require.Equal(t, "1:1: unexpected EQUALS \"=\", expected IDENT or EXPORT or COMMENT or EOL", err.Error())
+}
+
+func TestLoadReader(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("EXISTING", "original")
+
+	err := LoadReader(strings.NewReader("EXISTING=replaced\nNEW=${EXISTING}\njust some words"))
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"EXISTING=original", "NEW=original"}, os.Environ())
+}
+
+func TestLoadStrictReader(t *testing.T) {
+	os.Clearenv()
+
+	err := LoadStrictReader(strings.NewReader("VALID=1\njust some words"), "inline")
+	require.EqualError(t, err, "inline:2:6: unexpected IDENT \"some\", expected EQUALS")
+	require.Empty(t, os.Environ())
+}
+
+func TestOverloadReader(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("EXISTING", "original")
+
+	err := OverloadReader(strings.NewReader("EXISTING=replaced\nNEW=${EXISTING}\njust some words"))
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"EXISTING=replaced", "NEW=replaced"}, os.Environ())
+}
+
+func TestOverloadStrictReader(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("EXISTING", "original")
+
+	err := OverloadStrictReader(strings.NewReader("EXISTING=replaced"))
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"EXISTING=replaced"}, os.Environ())
+}
+
+func TestRead(t *testing.T) {
+	// NB: the process environment is cleared so the load expectations apply to the returned map
+	for _, tc := range loadTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Clearenv()
+
+			envars, err := Read(tc.files...)
+			require.Nil(t, err)
+
+			require.ElementsMatch(t, tc.expected, environ(envars))
+			require.Empty(t, os.Environ())
+		})
+	}
+}
+
+func TestReadStrict(t *testing.T) {
+	for _, tc := range loadStrictTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Clearenv()
+
+			envars, err := ReadStrict(tc.files...)
+			require.Equal(t, tc.expectedError, err)
+
+			if tc.expectedError == nil {
+				require.ElementsMatch(t, tc.expected, environ(envars))
+			} else {
+				require.Nil(t, envars)
+			}
+			require.Empty(t, os.Environ())
+		})
+	}
+}
+
+func TestReadIgnoresEnvironment(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("EXPORTED", "from env")
+	os.Setenv("FROM_ENV", "outside")
+
+	envars, err := Read("fixtures/basic.env")
+	require.Nil(t, err)
+	require.Equal(t, "data", envars["EXPORTED"])
+	require.NotContains(t, envars, "FROM_ENV")
+	require.ElementsMatch(t, []string{"EXPORTED=from env", "FROM_ENV=outside"}, os.Environ())
+}
+
+func TestReadExpansionFallback(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("OUTSIDE", "env")
+	os.Setenv("SHADOWED", "env")
+
+	path := filepath.Join(t.TempDir(), ".env")
+	require.Nil(t, os.WriteFile(path, []byte("SHADOWED=file\nVALUE=${SHADOWED}-${OUTSIDE}"), 0644))
+
+	envars, err := Read(path)
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"SHADOWED": "file", "VALUE": "file-env"}, envars)
+}
+
+func TestReadHeredocFallback(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected map[string]string
+	}{
+		{
+			"not a heredoc",
+			"ARROW=<<tag>>\nNEXT=1\nLAST=2",
+			map[string]string{"ARROW": "<<tag>>", "NEXT": "1", "LAST": "2"},
+		},
+		{
+			"unterminated heredoc",
+			"FIRST=0\nHEREDOC=<<EOF\nNEXT=1\nLAST=2",
+			map[string]string{"FIRST": "0", "NEXT": "1", "LAST": "2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Clearenv()
+
+			envars, err := ReadWith(WithReader(strings.NewReader(tc.data)))
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, envars)
+		})
+	}
+}
+
+func TestReadMissing(t *testing.T) {
+	envars, err := Read("fixtures/missing.env")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	require.Nil(t, envars)
+}
+
+func environ(envars map[string]string) []string {
+	list := make([]string, 0, len(envars))
+	for key, val := range envars {
+		list = append(list, key+"="+val)
+	}
+
+	return list
+}
+
+func TestLoadEnv(t *testing.T) {
+	for _, tc := range loadTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := NewMapEnv(nil)
+
+			err := LoadEnv(env, tc.files...)
+			require.Nil(t, err)
+
+			require.ElementsMatch(t, tc.expected, environ(env.Map()))
+		})
+	}
+}
+
+func TestLoadStrictEnv(t *testing.T) {
+	for _, tc := range loadStrictTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := NewMapEnv(nil)
+
+			err := LoadStrictEnv(env, tc.files...)
+			require.Equal(t, tc.expectedError, err)
+
+			require.ElementsMatch(t, tc.expected, environ(env.Map()))
+		})
+	}
+}
+
+func TestOverloadEnv(t *testing.T) {
+	for _, tc := range overloadTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := NewMapEnv(nil)
+
+			err := OverloadEnv(env, tc.files...)
+			require.Nil(t, err)
+
+			require.ElementsMatch(t, tc.expected, environ(env.Map()))
+		})
+	}
+}
+
+func TestOverloadStrictEnv(t *testing.T) {
+	for _, tc := range overloadStrictTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := NewMapEnv(nil)
+
+			err := OverloadStrictEnv(env, tc.files...)
+			require.Equal(t, tc.expectedError, err)
+
+			require.ElementsMatch(t, tc.expected, environ(env.Map()))
+		})
+	}
+}
+
+func TestLoadEnvExisting(t *testing.T) {
+	env := NewLayeredEnv(NewMapEnv(nil), NewMapEnv(map[string]string{"EXPORTED": "existing"}))
+
+	err := LoadEnv(env, "fixtures/basic.env")
+	require.Nil(t, err)
+
+	val, _ := env.Lookup("EXPORTED")
+	require.Equal(t, "existing", val)
+
+	err = OverloadEnv(env, "fixtures/basic.env")
+	require.Nil(t, err)
+
+	val, _ = env.Lookup("EXPORTED")
+	require.Equal(t, "data", val)
+}
+
+func TestLoadFS(t *testing.T) {
+	// NB: test cases are shared with the os based loaders, the fixture paths are all valid within
+	//     an os.DirFS rooted at the package directory
+	for _, tc := range loadTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Clearenv()
+
+			err := LoadFS(os.DirFS("."), tc.files...)
+			require.Nil(t, err)
+
+			require.ElementsMatch(t, tc.expected, os.Environ())
+		})
+	}
+}
+
+func TestLoadStrictFS(t *testing.T) {
+	for _, tc := range loadStrictTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Clearenv()
+
+			err := LoadStrictFS(os.DirFS("."), tc.files...)
+			require.Equal(t, tc.expectedError, err)
+
+			require.ElementsMatch(t, tc.expected, os.Environ())
+		})
+	}
+}
+
+func TestOverloadFS(t *testing.T) {
+	for _, tc := range overloadTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Clearenv()
+
+			err := OverloadFS(os.DirFS("."), tc.files...)
+			require.Nil(t, err)
+
+			require.ElementsMatch(t, tc.expected, os.Environ())
+		})
+	}
+}
+
+func TestOverloadStrictFS(t *testing.T) {
+	for _, tc := range overloadStrictTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Clearenv()
+
+			err := OverloadStrictFS(os.DirFS("."), tc.files...)
+			require.Equal(t, tc.expectedError, err)
+
+			require.ElementsMatch(t, tc.expected, os.Environ())
+		})
+	}
+}
+
+func TestLoadFSFallback(t *testing.T) {
+	os.Clearenv()
+
+	fsys := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte("FROM_FS=default")},
+	}
+
+	err := LoadFS(fsys)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"FROM_FS=default"}, os.Environ())
+}
+
+func TestLoadFSMissing(t *testing.T) {
+	err := LoadFS(fstest.MapFS{})
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestParseFileFS(t *testing.T) {
+	for _, tc := range parseTestCases {
+		t.Run(tc.file, func(t *testing.T) {
+			p, err := ParseFileFS(os.DirFS("."), tc.file)
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, p.Parse())
+		})
+	}
+}
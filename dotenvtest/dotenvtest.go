@@ -0,0 +1,95 @@
+// Package dotenvtest provides helpers for loading .env files in tests
+//
+// Variables are set with t.Setenv so they are restored when the test completes, the helpers can
+// therefore not be used in parallel tests.
+package dotenvtest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/indeedhat/dotenv"
+)
+
+// inlineName is used in place of a file path in any errors produced by Inline
+const inlineName = "inline"
+
+// Load loads the provided list of .env files for the duration of the test.
+// If no files are provided it will default to loading .env from the current working directory.
+//
+// Later files and the values in the files take precedence over existing variables, the test will
+// fail with the position of any invalid syntax found in the files
+func Load(t testing.TB, filepaths ...string) {
+	t.Helper()
+
+	envars, err := dotenv.ReadWith(dotenv.WithFiles(filepaths...), dotenv.WithOverride(), dotenv.WithStrict())
+	if err != nil {
+		t.Fatalf("dotenvtest: %s", err)
+		return
+	}
+
+	setenv(t, envars)
+}
+
+// Inline loads the env data for the duration of the test
+//
+// The values in the data take precedence over existing variables, the test will fail with the
+// position of any invalid syntax found in the data
+func Inline(t testing.TB, data string) {
+	t.Helper()
+
+	envars, err := dotenv.ReadWith(
+		dotenv.WithReader(strings.NewReader(data), inlineName),
+		dotenv.WithOverride(),
+		dotenv.WithStrict(),
+	)
+	if err != nil {
+		t.Fatalf("dotenvtest: %s", err)
+		return
+	}
+
+	setenv(t, envars)
+}
+
+func setenv(t testing.TB, envars map[string]string) {
+	t.Helper()
+
+	for key, val := range envars {
+		t.Setenv(key, val)
+	}
+}
+
+// runner runs the tests of a package, it is implemented by *testing.M
+type runner interface {
+	Run() int
+}
+
+// Main loads the provided list of .env files before running the tests of the package, if no files
+// are provided it will default to loading .env.test from the package directory if it exists.
+//
+// It is intended to be called from TestMain, the environment is restored once the tests have run
+//
+//	func TestMain(m *testing.M) {
+//		dotenvtest.Main(m)
+//	}
+func Main(m *testing.M, filepaths ...string) {
+	os.Exit(run(m, filepaths))
+}
+
+func run(m runner, filepaths []string) int {
+	if len(filepaths) == 0 {
+		filepaths = []string{".env.test?"}
+	}
+
+	snapshot := dotenv.Snapshot()
+	defer snapshot.Restore()
+
+	if err := dotenv.LoadWith(dotenv.WithFiles(filepaths...), dotenv.WithOverride(), dotenv.WithStrict()); err != nil {
+		fmt.Fprintf(os.Stderr, "dotenvtest: %s\n", err)
+		return 1
+	}
+
+	return m.Run()
+}
@@ -0,0 +1,121 @@
+package dotenvtest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fatalTB records the message passed to Fatalf rather than stopping the test
+type fatalTB struct {
+	testing.TB
+	fatal string
+}
+
+func (f *fatalTB) Fatalf(format string, args ...any) {
+	f.fatal = fmt.Sprintf(format, args...)
+}
+
+func TestLoad(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("EXPORTED", "original")
+
+	t.Run("load", func(t *testing.T) {
+		Load(t, "../fixtures/basic.env", "../fixtures/replacement.env")
+
+		require.Equal(t, "data", os.Getenv("EXPORTED"))
+		require.Equal(t, "some#data", os.Getenv("REPLACE_FROM_BASIC"))
+	})
+
+	require.ElementsMatch(t, []string{"EXPORTED=original"}, os.Environ())
+}
+
+func TestLoadInvalid(t *testing.T) {
+	os.Clearenv()
+
+	tb := &fatalTB{TB: t}
+	Load(tb, "../fixtures/broken.env")
+
+	require.Contains(t, tb.fatal, `../fixtures/broken.env:1:6: unexpected IDENT "some", expected EQUALS`)
+	require.Empty(t, os.Environ())
+}
+
+func TestInline(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("A", "original")
+
+	t.Run("inline", func(t *testing.T) {
+		Inline(t, "A=1\nB=${A}2")
+
+		require.Equal(t, "1", os.Getenv("A"))
+		require.Equal(t, "12", os.Getenv("B"))
+	})
+
+	require.ElementsMatch(t, []string{"A=original"}, os.Environ())
+}
+
+func TestInlineInvalid(t *testing.T) {
+	os.Clearenv()
+
+	tb := &fatalTB{TB: t}
+	Inline(tb, "A=1\njust some words")
+
+	require.Equal(t, `dotenvtest: inline:2:6: unexpected IDENT "some", expected EQUALS`, tb.fatal)
+	require.Empty(t, os.Environ())
+}
+
+// fakeRunner records the environment seen by the tests
+type fakeRunner struct {
+	environ []string
+}
+
+func (f *fakeRunner) Run() int {
+	f.environ = os.Environ()
+	return 3
+}
+
+func TestRun(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("EXPORTED", "original")
+
+	m := &fakeRunner{}
+	require.Equal(t, 3, run(m, []string{"../fixtures/basic.env"}))
+
+	require.Contains(t, m.environ, "EXPORTED=data")
+	require.ElementsMatch(t, []string{"EXPORTED=original"}, os.Environ())
+}
+
+func TestRunDefault(t *testing.T) {
+	os.Clearenv()
+	t.Chdir(t.TempDir())
+	require.Nil(t, os.WriteFile(".env.test", []byte("FROM_TEST=test"), 0644))
+
+	m := &fakeRunner{}
+	require.Equal(t, 3, run(m, nil))
+
+	require.Contains(t, m.environ, "FROM_TEST=test")
+	require.NotContains(t, os.Environ(), "FROM_TEST=test")
+}
+
+func TestRunDefaultMissing(t *testing.T) {
+	os.Clearenv()
+	t.Chdir(t.TempDir())
+	os.Setenv("EXPORTED", "original")
+
+	m := &fakeRunner{}
+	require.Equal(t, 3, run(m, nil))
+
+	require.Contains(t, m.environ, "EXPORTED=original")
+}
+
+func TestRunInvalid(t *testing.T) {
+	os.Clearenv()
+
+	m := &fakeRunner{}
+	require.Equal(t, 1, run(m, []string{"../fixtures/missing.env"}))
+
+	require.Nil(t, m.environ)
+	require.Empty(t, os.Environ())
+}
@@ -1,7 +1,7 @@
 package dotenv
 
 import (
-	"os"
+	"context"
 	"strconv"
 )
 
@@ -10,13 +10,33 @@ type EnVar[T any] interface {
 	Lookup(...T) T
 }
 
+// EnVarFrom is implemented by the envar types that can be read from any Environment
+type EnVarFrom[T any] interface {
+	GetFrom(Environment, ...T) T
+	LookupFrom(Environment, ...T) T
+}
+
+// EnVarCtx is implemented by the envar types that can be read from the Environment attached to a
+// context
+type EnVarCtx[T any] interface {
+	GetCtx(context.Context, ...T) T
+	LookupCtx(context.Context, ...T) T
+}
+
 type String string
 
 // Get the value of the String envar
 // If the value is an empty string or the variable is not found then any provided fallback value
 // will be returned
 func (k String) Get(fallback ...string) string {
-	val := os.Getenv(string(k))
+	return k.GetFrom(OSEnv{}, fallback...)
+}
+
+// GetFrom gets the value of the String envar from env
+// If the value is an empty string or the variable is not found then any provided fallback value
+// will be returned
+func (k String) GetFrom(env Environment, fallback ...string) string {
+	val, _ := env.Lookup(string(k))
 
 	if val == "" && len(fallback) > 0 {
 		return fallback[0]
@@ -29,7 +49,14 @@ func (k String) Get(fallback ...string) string {
 // If the value is found it will always be returned, any provided fallback value will only be used
 // if the envar does not exist
 func (k String) Lookup(fallback ...string) string {
-	val, ok := os.LookupEnv(string(k))
+	return k.LookupFrom(OSEnv{}, fallback...)
+}
+
+// LookupFrom returns the value for the String envar from env
+// If the value is found it will always be returned, any provided fallback value will only be used
+// if the envar does not exist
+func (k String) LookupFrom(env Environment, fallback ...string) string {
+	val, ok := env.Lookup(string(k))
 
 	if !ok && len(fallback) > 0 {
 		return fallback[0]
@@ -38,7 +65,25 @@ func (k String) Lookup(fallback ...string) string {
 	return val
 }
 
-var _ EnVar[string] = (*String)(nil)
+// GetCtx gets the value of the String envar from the Environment attached to ctx by WithEnv
+// If the value is an empty string or the variable is not found then any provided fallback value
+// will be returned
+func (k String) GetCtx(ctx context.Context, fallback ...string) string {
+	return k.GetFrom(EnvFrom(ctx), fallback...)
+}
+
+// LookupCtx returns the value for the String envar from the Environment attached to ctx by WithEnv
+// If the value is found it will always be returned, any provided fallback value will only be used
+// if the envar does not exist
+func (k String) LookupCtx(ctx context.Context, fallback ...string) string {
+	return k.LookupFrom(EnvFrom(ctx), fallback...)
+}
+
+var (
+	_ EnVar[string]     = (*String)(nil)
+	_ EnVarFrom[string] = (*String)(nil)
+	_ EnVarCtx[string]  = (*String)(nil)
+)
 
 type Int string
 
@@ -46,7 +91,14 @@ type Int string
 // If the value is an empty string or the variable is not found then any provided fallback value
 // will be returned
 func (k Int) Get(fallback ...int) int {
-	val := os.Getenv(string(k))
+	return k.GetFrom(OSEnv{}, fallback...)
+}
+
+// GetFrom gets the value of the Int envar from env
+// If the value is an empty string or the variable is not found then any provided fallback value
+// will be returned
+func (k Int) GetFrom(env Environment, fallback ...int) int {
+	val, _ := env.Lookup(string(k))
 
 	if val == "" && len(fallback) > 0 {
 		return fallback[0]
@@ -64,7 +116,14 @@ func (k Int) Get(fallback ...int) int {
 // If the value is found it will always be returned, any provided fallback value will only be used
 // if the envar does not exist
 func (k Int) Lookup(fallback ...int) int {
-	val, ok := os.LookupEnv(string(k))
+	return k.LookupFrom(OSEnv{}, fallback...)
+}
+
+// LookupFrom returns the value for the Int envar from env
+// If the value is found it will always be returned, any provided fallback value will only be used
+// if the envar does not exist
+func (k Int) LookupFrom(env Environment, fallback ...int) int {
+	val, ok := env.Lookup(string(k))
 
 	if !ok && len(fallback) > 0 {
 		return fallback[0]
@@ -74,7 +133,25 @@ func (k Int) Lookup(fallback ...int) int {
 	return int(parsed)
 }
 
-var _ EnVar[int] = (*Int)(nil)
+// GetCtx gets the value of the Int envar from the Environment attached to ctx by WithEnv
+// If the value is an empty string or the variable is not found then any provided fallback value
+// will be returned
+func (k Int) GetCtx(ctx context.Context, fallback ...int) int {
+	return k.GetFrom(EnvFrom(ctx), fallback...)
+}
+
+// LookupCtx returns the value for the Int envar from the Environment attached to ctx by WithEnv
+// If the value is found it will always be returned, any provided fallback value will only be used
+// if the envar does not exist
+func (k Int) LookupCtx(ctx context.Context, fallback ...int) int {
+	return k.LookupFrom(EnvFrom(ctx), fallback...)
+}
+
+var (
+	_ EnVar[int]     = (*Int)(nil)
+	_ EnVarFrom[int] = (*Int)(nil)
+	_ EnVarCtx[int]  = (*Int)(nil)
+)
 
 type Float string
 
@@ -82,7 +159,14 @@ type Float string
 // If the value is an empty string or the variable is not found then any provided fallback value
 // will be returned
 func (k Float) Get(fallback ...float64) float64 {
-	val := os.Getenv(string(k))
+	return k.GetFrom(OSEnv{}, fallback...)
+}
+
+// GetFrom gets the value of the Float envar from env
+// If the value is an empty string or the variable is not found then any provided fallback value
+// will be returned
+func (k Float) GetFrom(env Environment, fallback ...float64) float64 {
+	val, _ := env.Lookup(string(k))
 
 	if val == "" && len(fallback) > 0 {
 		return fallback[0]
@@ -100,7 +184,14 @@ func (k Float) Get(fallback ...float64) float64 {
 // If the value is found it will always be returned, any provided fallback value will only be used
 // if the envar does not exist
 func (k Float) Lookup(fallback ...float64) float64 {
-	val, ok := os.LookupEnv(string(k))
+	return k.LookupFrom(OSEnv{}, fallback...)
+}
+
+// LookupFrom returns the value for the Float envar from env
+// If the value is found it will always be returned, any provided fallback value will only be used
+// if the envar does not exist
+func (k Float) LookupFrom(env Environment, fallback ...float64) float64 {
+	val, ok := env.Lookup(string(k))
 
 	if !ok && len(fallback) > 0 {
 		return fallback[0]
@@ -110,7 +201,25 @@ func (k Float) Lookup(fallback ...float64) float64 {
 	return parsed
 }
 
-var _ EnVar[float64] = (*Float)(nil)
+// GetCtx gets the value of the Float envar from the Environment attached to ctx by WithEnv
+// If the value is an empty string or the variable is not found then any provided fallback value
+// will be returned
+func (k Float) GetCtx(ctx context.Context, fallback ...float64) float64 {
+	return k.GetFrom(EnvFrom(ctx), fallback...)
+}
+
+// LookupCtx returns the value for the Float envar from the Environment attached to ctx by WithEnv
+// If the value is found it will always be returned, any provided fallback value will only be used
+// if the envar does not exist
+func (k Float) LookupCtx(ctx context.Context, fallback ...float64) float64 {
+	return k.LookupFrom(EnvFrom(ctx), fallback...)
+}
+
+var (
+	_ EnVar[float64]     = (*Float)(nil)
+	_ EnVarFrom[float64] = (*Float)(nil)
+	_ EnVarCtx[float64]  = (*Float)(nil)
+)
 
 type Bool string
 
@@ -118,7 +227,14 @@ type Bool string
 // If the value is an empty string or the variable is not found then any provided fallback value
 // will be returned
 func (k Bool) Get(fallback ...bool) bool {
-	val := os.Getenv(string(k))
+	return k.GetFrom(OSEnv{}, fallback...)
+}
+
+// GetFrom gets the value of the Bool envar from env
+// If the value is an empty string or the variable is not found then any provided fallback value
+// will be returned
+func (k Bool) GetFrom(env Environment, fallback ...bool) bool {
+	val, _ := env.Lookup(string(k))
 
 	if val == "" && len(fallback) > 0 {
 		return fallback[0]
@@ -136,7 +252,14 @@ func (k Bool) Get(fallback ...bool) bool {
 // If the value is found it will always be returned, any provided fallback value will only be used
 // if the envar does not exist
 func (k Bool) Lookup(fallback ...bool) bool {
-	val, ok := os.LookupEnv(string(k))
+	return k.LookupFrom(OSEnv{}, fallback...)
+}
+
+// LookupFrom returns the value for the Bool envar from env
+// If the value is found it will always be returned, any provided fallback value will only be used
+// if the envar does not exist
+func (k Bool) LookupFrom(env Environment, fallback ...bool) bool {
+	val, ok := env.Lookup(string(k))
 
 	if !ok && len(fallback) > 0 {
 		return fallback[0]
@@ -146,4 +269,22 @@ func (k Bool) Lookup(fallback ...bool) bool {
 	return parsed
 }
 
-var _ EnVar[bool] = (*Bool)(nil)
+// GetCtx gets the value of the Bool envar from the Environment attached to ctx by WithEnv
+// If the value is an empty string or the variable is not found then any provided fallback value
+// will be returned
+func (k Bool) GetCtx(ctx context.Context, fallback ...bool) bool {
+	return k.GetFrom(EnvFrom(ctx), fallback...)
+}
+
+// LookupCtx returns the value for the Bool envar from the Environment attached to ctx by WithEnv
+// If the value is found it will always be returned, any provided fallback value will only be used
+// if the envar does not exist
+func (k Bool) LookupCtx(ctx context.Context, fallback ...bool) bool {
+	return k.LookupFrom(EnvFrom(ctx), fallback...)
+}
+
+var (
+	_ EnVar[bool]     = (*Bool)(nil)
+	_ EnVarFrom[bool] = (*Bool)(nil)
+	_ EnVarCtx[bool]  = (*Bool)(nil)
+)
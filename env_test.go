@@ -1,6 +1,7 @@
 package dotenv
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -305,3 +306,93 @@ func TestBoolLookup(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFrom(t *testing.T) {
+	t.Run("String", func(t *testing.T) { testGetFrom(t, stringTestCases) })
+	t.Run("Int", func(t *testing.T) { testGetFrom(t, intTestCases) })
+	t.Run("Float", func(t *testing.T) { testGetFrom(t, floatTestCases) })
+	t.Run("Bool", func(t *testing.T) { testGetFrom(t, boolTestCases) })
+}
+
+func TestLookupFrom(t *testing.T) {
+	t.Run("String", func(t *testing.T) { testLookupFrom(t, stringTestCases) })
+	t.Run("Int", func(t *testing.T) { testLookupFrom(t, intTestCases) })
+	t.Run("Float", func(t *testing.T) { testLookupFrom(t, floatTestCases) })
+	t.Run("Bool", func(t *testing.T) { testLookupFrom(t, boolTestCases) })
+}
+
+// NB: the From variants read from their own environment so unlike the os based tests above they
+// are safe to run in parallel
+func testGetFrom[S interface {
+	EnVar[T]
+	EnVarFrom[T]
+	~string
+}, T any](t *testing.T, cases []envarTestCase[S, T]) {
+	for _, tc := range cases {
+		t.Run(string(tc.subject), func(t *testing.T) {
+			t.Parallel()
+
+			env := NewMapEnv(tc.env)
+			require.Equal(t, tc.getExpected, tc.subject.GetFrom(env, tc.fallback...))
+		})
+	}
+}
+
+func testLookupFrom[S interface {
+	EnVar[T]
+	EnVarFrom[T]
+	~string
+}, T any](t *testing.T, cases []envarTestCase[S, T]) {
+	for _, tc := range cases {
+		t.Run(string(tc.subject), func(t *testing.T) {
+			t.Parallel()
+
+			env := NewMapEnv(tc.env)
+			require.Equal(t, tc.lookupExpected, tc.subject.LookupFrom(env, tc.fallback...))
+		})
+	}
+}
+
+func TestGetCtx(t *testing.T) {
+	t.Run("String", func(t *testing.T) { testGetCtx(t, stringTestCases) })
+	t.Run("Int", func(t *testing.T) { testGetCtx(t, intTestCases) })
+	t.Run("Float", func(t *testing.T) { testGetCtx(t, floatTestCases) })
+	t.Run("Bool", func(t *testing.T) { testGetCtx(t, boolTestCases) })
+}
+
+func TestLookupCtx(t *testing.T) {
+	t.Run("String", func(t *testing.T) { testLookupCtx(t, stringTestCases) })
+	t.Run("Int", func(t *testing.T) { testLookupCtx(t, intTestCases) })
+	t.Run("Float", func(t *testing.T) { testLookupCtx(t, floatTestCases) })
+	t.Run("Bool", func(t *testing.T) { testLookupCtx(t, boolTestCases) })
+}
+
+func testGetCtx[S interface {
+	EnVar[T]
+	EnVarCtx[T]
+	~string
+}, T any](t *testing.T, cases []envarTestCase[S, T]) {
+	for _, tc := range cases {
+		t.Run(string(tc.subject), func(t *testing.T) {
+			t.Parallel()
+
+			ctx := WithEnv(context.Background(), NewMapEnv(tc.env))
+			require.Equal(t, tc.getExpected, tc.subject.GetCtx(ctx, tc.fallback...))
+		})
+	}
+}
+
+func testLookupCtx[S interface {
+	EnVar[T]
+	EnVarCtx[T]
+	~string
+}, T any](t *testing.T, cases []envarTestCase[S, T]) {
+	for _, tc := range cases {
+		t.Run(string(tc.subject), func(t *testing.T) {
+			t.Parallel()
+
+			ctx := WithEnv(context.Background(), NewMapEnv(tc.env))
+			require.Equal(t, tc.lookupExpected, tc.subject.LookupCtx(ctx, tc.fallback...))
+		})
+	}
+}
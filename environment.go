@@ -0,0 +1,284 @@
+package dotenv
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Environment is a store of environment variables that env files can be loaded into and the
+// helper types can read from
+type Environment interface {
+	// Lookup returns the value of the variable and whether it exists
+	Lookup(key string) (string, bool)
+	// Set the value of the variable
+	Set(key, value string) error
+	// Unset removes the variable
+	Unset(key string) error
+	// Keys returns the sorted names of all the variables that exist
+	Keys() []string
+}
+
+// OSEnv is the Environment of the running process
+type OSEnv struct{}
+
+// Lookup returns the value of the variable from the os.environment
+func (OSEnv) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// Set the value of the variable in the os.environment
+func (OSEnv) Set(key, value string) error {
+	return os.Setenv(key, value)
+}
+
+// Unset removes the variable from the os.environment
+func (OSEnv) Unset(key string) error {
+	return os.Unsetenv(key)
+}
+
+// Keys returns the sorted names of all the variables in the os.environment
+func (OSEnv) Keys() []string {
+	var keys []string
+
+	for _, envar := range os.Environ() {
+		// NB: windows keeps some special variables in the form =C:=C:\ so an empty key is skipped
+		key, _, _ := strings.Cut(envar, "=")
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+var _ Environment = OSEnv{}
+
+// MapEnv is an in memory Environment, it is safe for concurrent use
+type MapEnv struct {
+	mux    sync.RWMutex
+	envars map[string]string
+}
+
+// NewMapEnv creates a MapEnv holding a copy of the provided envars
+func NewMapEnv(envars map[string]string) *MapEnv {
+	env := &MapEnv{
+		envars: make(map[string]string, len(envars)),
+	}
+
+	for key, val := range envars {
+		env.envars[key] = val
+	}
+
+	return env
+}
+
+// Lookup returns the value of the variable and whether it exists
+func (e *MapEnv) Lookup(key string) (string, bool) {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+
+	val, ok := e.envars[key]
+	return val, ok
+}
+
+// Set the value of the variable
+func (e *MapEnv) Set(key, value string) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	e.envars[key] = value
+
+	return nil
+}
+
+// Unset removes the variable
+func (e *MapEnv) Unset(key string) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	delete(e.envars, key)
+
+	return nil
+}
+
+// Keys returns the sorted names of all the variables
+func (e *MapEnv) Keys() []string {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+
+	keys := make([]string, 0, len(e.envars))
+	for key := range e.envars {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Map returns a copy of all the variables
+func (e *MapEnv) Map() map[string]string {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+
+	envars := make(map[string]string, len(e.envars))
+	for key, val := range e.envars {
+		envars[key] = val
+	}
+
+	return envars
+}
+
+var _ Environment = (*MapEnv)(nil)
+
+// LayeredEnv is a read through stack of environments
+//
+// Lookups check each layer in turn starting from the top, all writes are made to the top layer.
+// Unsetting a variable will hide it in all of the lower layers without modifying them.
+type LayeredEnv struct {
+	mux    sync.RWMutex
+	layers []Environment
+	masked map[string]struct{}
+}
+
+// NewLayeredEnv creates a LayeredEnv from the provided layers, the first layer is the top of the
+// stack and will receive all writes
+//
+// If no layers are provided a new MapEnv will be used as the only layer
+func NewLayeredEnv(layers ...Environment) *LayeredEnv {
+	if len(layers) == 0 {
+		layers = []Environment{NewMapEnv(nil)}
+	}
+
+	return &LayeredEnv{
+		layers: layers,
+		masked: make(map[string]struct{}),
+	}
+}
+
+// Lookup returns the value of the variable from the first layer that contains it
+func (e *LayeredEnv) Lookup(key string) (string, bool) {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+
+	if _, ok := e.masked[key]; ok {
+		return "", false
+	}
+
+	for _, layer := range e.layers {
+		if val, ok := layer.Lookup(key); ok {
+			return val, true
+		}
+	}
+
+	return "", false
+}
+
+// Set the value of the variable in the top layer
+func (e *LayeredEnv) Set(key, value string) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if err := e.layers[0].Set(key, value); err != nil {
+		return err
+	}
+
+	delete(e.masked, key)
+
+	return nil
+}
+
+// Unset removes the variable from the top layer and hides it in all of the lower layers
+func (e *LayeredEnv) Unset(key string) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if err := e.layers[0].Unset(key); err != nil {
+		return err
+	}
+
+	e.masked[key] = struct{}{}
+
+	return nil
+}
+
+// Keys returns the sorted names of all the variables visible through the stack
+func (e *LayeredEnv) Keys() []string {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+
+	var keys []string
+	seen := make(map[string]struct{})
+
+	for _, layer := range e.layers {
+		for _, key := range layer.Keys() {
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			if _, ok := e.masked[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+var _ Environment = (*LayeredEnv)(nil)
+
+// PrefixedEnv is a view of the variables in an Environment that start with a prefix, the prefix is
+// hidden from the keys of the view.
+//
+// This allows the helper types to read variables that were loaded with a prefix:
+//
+//	String("DB_URL").GetFrom(NewPrefixedEnv(OSEnv{}, "BILLING_")) // reads BILLING_DB_URL
+type PrefixedEnv struct {
+	env    Environment
+	prefix string
+}
+
+// NewPrefixedEnv creates a PrefixedEnv for the variables in env that start with prefix
+func NewPrefixedEnv(env Environment, prefix string) *PrefixedEnv {
+	return &PrefixedEnv{
+		env:    env,
+		prefix: prefix,
+	}
+}
+
+// Lookup returns the value of the prefixed variable
+func (e *PrefixedEnv) Lookup(key string) (string, bool) {
+	return e.env.Lookup(e.prefix + key)
+}
+
+// Set the value of the prefixed variable
+func (e *PrefixedEnv) Set(key, value string) error {
+	return e.env.Set(e.prefix+key, value)
+}
+
+// Unset removes the prefixed variable
+func (e *PrefixedEnv) Unset(key string) error {
+	return e.env.Unset(e.prefix + key)
+}
+
+// Keys returns the sorted names of all the prefixed variables with the prefix removed
+func (e *PrefixedEnv) Keys() []string {
+	var keys []string
+
+	for _, key := range e.env.Keys() {
+		if trimmed, ok := strings.CutPrefix(key, e.prefix); ok {
+			keys = append(keys, trimmed)
+		}
+	}
+
+	return keys
+}
+
+var _ Environment = (*PrefixedEnv)(nil)
@@ -0,0 +1,91 @@
+package dotenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSEnv(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("EXISTING", "value")
+
+	env := OSEnv{}
+
+	val, ok := env.Lookup("EXISTING")
+	require.True(t, ok)
+	require.Equal(t, "value", val)
+
+	require.Nil(t, env.Set("NEW", "new value"))
+	require.Equal(t, "new value", os.Getenv("NEW"))
+	require.Equal(t, []string{"EXISTING", "NEW"}, env.Keys())
+
+	require.Nil(t, env.Unset("EXISTING"))
+	_, ok = os.LookupEnv("EXISTING")
+	require.False(t, ok)
+}
+
+func TestMapEnv(t *testing.T) {
+	source := map[string]string{"EXISTING": "value", "EMPTY": ""}
+	env := NewMapEnv(source)
+
+	val, ok := env.Lookup("EMPTY")
+	require.True(t, ok)
+	require.Equal(t, "", val)
+
+	_, ok = env.Lookup("MISSING")
+	require.False(t, ok)
+
+	require.Nil(t, env.Set("NEW", "new value"))
+	require.Nil(t, env.Unset("EXISTING"))
+	require.Equal(t, []string{"EMPTY", "NEW"}, env.Keys())
+	require.Equal(t, map[string]string{"EMPTY": "", "NEW": "new value"}, env.Map())
+
+	// the source map is copied so should not be modified
+	require.Equal(t, map[string]string{"EXISTING": "value", "EMPTY": ""}, source)
+}
+
+func TestLayeredEnv(t *testing.T) {
+	top := NewMapEnv(map[string]string{"SHADOWED": "top"})
+	bottom := NewMapEnv(map[string]string{"SHADOWED": "bottom", "BOTTOM": "bottom"})
+	env := NewLayeredEnv(top, bottom)
+
+	val, ok := env.Lookup("SHADOWED")
+	require.True(t, ok)
+	require.Equal(t, "top", val)
+	require.Equal(t, []string{"BOTTOM", "SHADOWED"}, env.Keys())
+
+	require.Nil(t, env.Set("BOTTOM", "top"))
+	require.Equal(t, map[string]string{"SHADOWED": "top", "BOTTOM": "top"}, top.Map())
+	require.Equal(t, map[string]string{"SHADOWED": "bottom", "BOTTOM": "bottom"}, bottom.Map())
+
+	require.Nil(t, env.Unset("SHADOWED"))
+	_, ok = env.Lookup("SHADOWED")
+	require.False(t, ok)
+	require.Equal(t, []string{"BOTTOM"}, env.Keys())
+	require.Equal(t, map[string]string{"SHADOWED": "bottom", "BOTTOM": "bottom"}, bottom.Map())
+
+	require.Nil(t, env.Set("SHADOWED", "restored"))
+	val, _ = env.Lookup("SHADOWED")
+	require.Equal(t, "restored", val)
+}
+
+func TestLayeredEnvDefaultLayer(t *testing.T) {
+	env := NewLayeredEnv()
+
+	require.Nil(t, env.Set("KEY", "value"))
+	require.Equal(t, []string{"KEY"}, env.Keys())
+}
+
+func TestPrefixedEnv(t *testing.T) {
+	source := NewMapEnv(map[string]string{"BILLING_DB_URL": "billing", "SHIPPING_DB_URL": "shipping"})
+	env := NewPrefixedEnv(source, "BILLING_")
+
+	require.Equal(t, "billing", String("DB_URL").GetFrom(env))
+	require.Equal(t, []string{"DB_URL"}, env.Keys())
+
+	require.Nil(t, env.Set("DB_HOST", "db.local"))
+	require.Nil(t, env.Unset("DB_URL"))
+	require.Equal(t, map[string]string{"BILLING_DB_HOST": "db.local", "SHIPPING_DB_URL": "shipping"}, source.Map())
+}
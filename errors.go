@@ -0,0 +1,80 @@
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError describes an unexpected token found while strictly parsing a .env file
+//
+// It can be retrieved from the errors returned by ParseStrict and the strict load operations with
+// errors.As
+type SyntaxError struct {
+	// File is the path of the file being parsed, it will be empty if the source was not a file
+	File string
+	// Line is the 1-based line number the offending token starts on
+	Line int
+	// Column is the 1-based column the offending token starts at
+	Column int
+	// Token is the kind of the offending token
+	Token string
+	// Literal is the text of the offending token
+	Literal string
+	// Expected lists the token kinds that would have been accepted in its place
+	Expected []string
+	// Reason describes why the token is invalid, when set it is reported in place of the expected
+	// token kinds
+	Reason string
+	// Source is the full line of the source that contains the offending token
+	Source string
+}
+
+// Error implements the error interface
+func (e *SyntaxError) Error() string {
+	var buf strings.Builder
+
+	if e.File != "" {
+		buf.WriteString(e.File)
+		buf.WriteByte(':')
+	}
+
+	if e.Reason != "" {
+		fmt.Fprintf(&buf, "%d:%d: %s", e.Line, e.Column, e.Reason)
+	} else {
+		fmt.Fprintf(&buf, "%d:%d: unexpected %s", e.Line, e.Column, e.Token)
+	}
+
+	if e.Literal != "" {
+		fmt.Fprintf(&buf, " %q", e.Literal)
+	}
+
+	if len(e.Expected) > 0 {
+		fmt.Fprintf(&buf, ", expected %s", strings.Join(e.Expected, " or "))
+	}
+
+	return buf.String()
+}
+
+// ProtectedKeyError describes an attempt to set a protected key from a .env file
+//
+// It is returned by load operations using WithProtectedKeys with the ProtectError action
+type ProtectedKeyError struct {
+	// File is the path of the file the key was found in, it will be empty if the source was not a
+	// file
+	File string
+	// Line is the 1-based line number of the key
+	Line int
+	// Column is the 1-based column of the key
+	Column int
+	// Key is the protected key
+	Key string
+}
+
+// Error implements the error interface
+func (e *ProtectedKeyError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%d:%d: refusing to set protected key %q", e.Line, e.Column, e.Key)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: refusing to set protected key %q", e.File, e.Line, e.Column, e.Key)
+}
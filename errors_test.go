@@ -0,0 +1,104 @@
+package dotenv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var syntaxErrorTestCases = []struct {
+	name     string
+	err      *SyntaxError
+	expected string
+}{
+	{
+		"with file",
+		&SyntaxError{
+			File:     ".env",
+			Line:     1,
+			Column:   6,
+			Token:    tknIdentifier,
+			Literal:  "some",
+			Expected: []string{tknEquals},
+		},
+		`.env:1:6: unexpected IDENT "some", expected EQUALS`,
+	},
+	{
+		"without file",
+		&SyntaxError{
+			Line:     3,
+			Column:   1,
+			Token:    tknEquals,
+			Literal:  "=",
+			Expected: []string{tknIdentifier, tknExport},
+		},
+		`3:1: unexpected EQUALS "=", expected IDENT or EXPORT`,
+	},
+	{
+		"without literal",
+		&SyntaxError{
+			Line:   2,
+			Column: 9,
+			Token:  tknIllegal,
+		},
+		`2:9: unexpected ILLEGAL`,
+	},
+	{
+		"with reason",
+		&SyntaxError{
+			File:    ".env",
+			Line:    2,
+			Column:  14,
+			Token:   tknIllegal,
+			Literal: "\\q",
+			Reason:  "invalid escape sequence",
+		},
+		`.env:2:14: invalid escape sequence "\\q"`,
+	},
+}
+
+func TestSyntaxErrorError(t *testing.T) {
+	for _, tc := range syntaxErrorTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.err.Error())
+		})
+	}
+}
+
+func TestSyntaxErrorAs(t *testing.T) {
+	err := LoadStrict("fixtures/basic.env", "fixtures/broken.env")
+
+	var syntaxErr *SyntaxError
+	require.True(t, errors.As(err, &syntaxErr))
+	require.Equal(t, "fixtures/broken.env", syntaxErr.File)
+	require.Equal(t, 1, syntaxErr.Line)
+	require.Equal(t, 6, syntaxErr.Column)
+}
+
+func TestSyntaxErrorReason(t *testing.T) {
+	testCases := []struct {
+		data     string
+		expected string
+	}{
+		{`A="bad \q escape"`, `1:8: invalid escape sequence "\\q"`},
+		{`A="unterminated`, `1:3: unterminated quote`},
+		{`A="""unterminated`, `1:3: unterminated quote`},
+		{"A=<<EOF\nunterminated", `1:3: unterminated heredoc`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.data, func(t *testing.T) {
+			_, err := ParseString(testCase.data).ParseStrict()
+			require.EqualError(t, err, testCase.expected)
+		})
+	}
+}
+
+func TestProtectedKeyErrorError(t *testing.T) {
+	err := &ProtectedKeyError{File: ".env", Line: 2, Column: 1, Key: "PATH"}
+	require.EqualError(t, err, `.env:2:1: refusing to set protected key "PATH"`)
+
+	err.File = ""
+	require.EqualError(t, err, `2:1: refusing to set protected key "PATH"`)
+}
@@ -1,15 +1,26 @@
 package dotenv
 
+import (
+	"slices"
+	"strings"
+)
+
 // Expand replaces ${var} or $var in the string based on the mapping function.
 // For example, [os.ExpandEnv](s) is equivalent to [os.Expand](s, [os.Getenv]).
 //
 // This is a modified version of the Expand function from os that handles escaped characters
 func Expand(s string, mapping func(string) string) string {
+	return expand(s, mapping, true, nil)
+}
+
+// expand replaces the variables in s, backslash escapes are only handled if unescape is set and
+// the $ at each of the masked byte offsets is always kept literal
+func expand(s string, mapping func(string) string, unescape bool, masked []int) string {
 	var buf []byte
 	// ${} is all ASCII, so bytes are fine for this operation.
 	i := 0
 	for j := 0; j < len(s); j++ {
-		if s[j] == '\\' && j+1 < len(s) && (s[j+1] == '$' || s[j+1] == '\\') {
+		if unescape && s[j] == '\\' && j+1 < len(s) && (s[j+1] == '$' || s[j+1] == '\\') {
 			if buf == nil {
 				buf = make([]byte, 0, 2*len(s))
 			}
@@ -22,12 +33,20 @@ func Expand(s string, mapping func(string) string) string {
 			continue
 		}
 
-		if s[j] == '$' && j+1 < len(s) {
+		if s[j] == '$' && j+1 < len(s) && !slices.Contains(masked, j) {
 			if buf == nil {
 				buf = make([]byte, 0, 2*len(s))
 			}
 			buf = append(buf, s[i:j]...)
-			name, w := getShellName(s[j+1:])
+
+			// NB: a masked $ cannot be the name of the special $$ variable
+			var (
+				name string
+				w    int
+			)
+			if !slices.Contains(masked, j+1) {
+				name, w = getShellName(s[j+1:])
+			}
 			if name == "" && w > 0 {
 				// Encountered invalid syntax; eat the
 				// characters.
@@ -48,6 +67,41 @@ func Expand(s string, mapping func(string) string) string {
 	return string(buf) + s[i:]
 }
 
+// unescaper removes the escapes handled by Expand without expanding any variables
+var unescaper = strings.NewReplacer(`\\\\`, `\\`, `\\$`, `$`)
+
+// expand returns the value of the entry with its variables expanded by mapping
+//
+// Double quoted values have already had their escape sequences decoded by the lexer so only the
+// masked $ need to be protected, any other value may still contain the \\ and \$ escapes that
+// Expand handles
+func (e ParseEntry) expand(mapping func(string) string) string {
+	if e.Quote == QuoteDouble {
+		return expand(e.Value, mapping, false, e.masked)
+	}
+
+	return Expand(e.Value, mapping)
+}
+
+// unescape returns the value of the entry without expanding its variables
+func (e ParseEntry) unescape() string {
+	if e.Quote == QuoteDouble {
+		return e.Value
+	}
+
+	return unescaper.Replace(e.Value)
+}
+
+// written returns the value of the entry as it was written in the file, escape sequences and line
+// continuations are left as they are
+func (e ParseEntry) written() string {
+	if e.source != "" {
+		return e.source
+	}
+
+	return e.Value
+}
+
 // isShellSpecialVar reports whether the character identifies a special
 // shell variable such as $*.
 func isShellSpecialVar(c uint8) bool {
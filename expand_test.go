@@ -59,3 +59,30 @@ func TestExpand(t *testing.T) {
 		}
 	}
 }
+
+var expandEntryTests = []struct {
+	in, out string
+}{
+	{`"${HOME}"`, "/usr/gopher"},
+	{`"\${HOME}"`, "${HOME}"},
+	{`"\\${HOME}"`, `\/usr/gopher`},
+	{`"\\\${HOME}"`, `\${HOME}`},
+	{`"\x24{HOME}"`, "${HOME}"},
+	{`"$\$"`, "$$"},
+	{`${HOME}`, "/usr/gopher"},
+	{`\${HOME}`, "${HOME}"},
+	{`\\${HOME}`, `\/usr/gopher`},
+}
+
+func TestParseEntryExpand(t *testing.T) {
+	for _, test := range expandEntryTests {
+		pairs, err := ParseString("A=" + test.in).ParseStrict()
+		if err != nil {
+			t.Fatalf("ParseString(%q) failed: %s", test.in, err)
+		}
+
+		if result := pairs[0].expand(testGetenv); result != test.out {
+			t.Errorf("expand(%q)=%q; expected %q", test.in, result, test.out)
+		}
+	}
+}
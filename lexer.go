@@ -3,23 +3,26 @@ package dotenv
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // unicode DEL is pretty unlikely to be present in a .env file
 const runeEOF = 0x7f
 
 const (
-	tknExport     = "EXPORT"
-	tknIdentifier = "IDENT"
-	tknEquals     = "EQUALS"
-	tknValue      = "VALUE"
-	tknRawValue   = "RAW_VALUE"
-	tknComment    = "COMMENT"
-	tknEOL        = "EOL"
-	tknEOF        = "EOF"
-	tknIllegal    = "ILLEGAL"
+	tknExport        = "EXPORT"
+	tknIdentifier    = "IDENT"
+	tknEquals        = "EQUALS"
+	tknValue         = "VALUE"
+	tknRawValue      = "RAW_VALUE"
+	tknBacktickValue = "BACKTICK_VALUE"
+	tknComment       = "COMMENT"
+	tknEOL           = "EOL"
+	tknEOF           = "EOF"
+	tknIllegal       = "ILLEGAL"
 )
 
 type token struct {
@@ -27,6 +30,15 @@ type token struct {
 	Pos     int
 	Type    string
 	Literal string
+	Quote   QuoteStyle
+	// Masked holds the byte offsets of any escaped $ in the literal, these must not be treated as
+	// the start of a variable during expansion
+	Masked []int
+	// Reason describes why an ILLEGAL token is invalid, it may be empty
+	Reason string
+	// Source holds the value as it was written if reading it changed the literal, it is empty
+	// otherwise
+	Source string
 }
 
 func (t token) With(typ, value string) token {
@@ -35,9 +47,38 @@ func (t token) With(typ, value string) token {
 		Pos:     t.Pos,
 		Type:    typ,
 		Literal: value,
+		Quote:   t.Quote,
 	}
 }
 
+// invalid returns an ILLEGAL copy of the token with the reason it is invalid
+func (t token) invalid(literal, reason string) token {
+	t = t.With(tknIllegal, literal)
+	t.Reason = reason
+	return t
+}
+
+// masking returns a copy of the token with the given masked offsets
+func (t token) masking(masked []int) token {
+	t.Masked = masked
+	return t
+}
+
+// written returns a copy of the token with the source it was read from, the source is only kept
+// if it differs from the literal
+func (t token) written(source string) token {
+	if source != t.Literal {
+		t.Source = source
+	}
+	return t
+}
+
+// quoted returns a copy of the token marked with the given quote style
+func (t token) quoted(quote QuoteStyle) token {
+	t.Quote = quote
+	return t
+}
+
 func (t token) String() string {
 	return fmt.Sprintf("%s value=%s line=%d pos=%d",
 		t.Type,
@@ -83,17 +124,9 @@ func (l *lexer) NextToken() token {
 				goto skip
 			}
 
-			tkn := l.tkn().With(tknEOL, "")
-			l.line++
-			l.linePos = 0
-
-			return tkn
+			return l.tkn().With(tknEOL, "")
 		case '\n':
-			tkn := l.tkn().With(tknEOL, "")
-			l.line++
-			l.linePos = 0
-
-			return tkn
+			return l.tkn().With(tknEOL, "")
 		case '\t', '\v', '\f', ' ', 0x85, 0xA0:
 			l.readRune()
 			goto skip
@@ -104,16 +137,18 @@ func (l *lexer) NextToken() token {
 			return l.tkn().With(tknComment, l.readComment())
 		case '=':
 			return l.tkn().With(tknEquals, "=")
-		case '\'', '"':
-			tkn := l.tkn()
-			str, ok := l.readQuotedString(l.char)
-			if !ok {
-				return tkn.With(tknIllegal, str)
+		case '\'':
+			if l.peekTripleQuote() {
+				return l.readTripleQuotedString(l.tkn().quoted(QuoteSingle), tknRawValue)
 			}
-			if l.char == '\'' {
-				return tkn.With(tknRawValue, str)
+			return l.readQuotedString(l.tkn().quoted(QuoteSingle), tknRawValue)
+		case '"':
+			if l.peekTripleQuote() {
+				return l.readTripleQuotedString(l.tkn().quoted(QuoteDouble), tknValue)
 			}
-			return tkn.With(tknValue, str)
+			return l.readQuotedString(l.tkn().quoted(QuoteDouble), tknValue)
+		case '`':
+			return l.readQuotedString(l.tkn().quoted(QuoteBacktick), tknBacktickValue)
 		case 'e':
 			if l.peekIdentifier() == "export" {
 				defer l.readIdentifier()
@@ -129,8 +164,12 @@ func (l *lexer) NextToken() token {
 				return l.tkn().With(tknIdentifier, l.readIdentifier())
 			}
 
+			if l.char == '<' && l.peekRune() == '<' && l.peekHeredoc() {
+				return l.readHeredoc(l.tkn().quoted(QuoteHeredoc))
+			}
+
 			skipRead = true
-			return l.tkn().With(tknValue, l.readUnquotedString())
+			return l.readUnquotedString(l.tkn())
 		}
 	})()
 
@@ -146,6 +185,13 @@ func (l *lexer) tkn() token {
 }
 
 func (l *lexer) readRune() {
+	// moving past a line break puts us at the start of the next line, a \r only counts if it is
+	// not part of a \r\n pair
+	if l.char == '\n' || l.char == '\r' && l.peekRune() != '\n' {
+		l.line++
+		l.linePos = 0
+	}
+
 	if l.readPos >= len(l.data) {
 		l.char = runeEOF
 	} else {
@@ -158,11 +204,21 @@ func (l *lexer) readRune() {
 }
 
 func (l *lexer) peekRune() rune {
-	if l.readPos >= len(l.data) {
+	return l.peekRuneAt(1)
+}
+
+// peekRuneAt returns the rune n places ahead of the cursor without moving it
+func (l *lexer) peekRuneAt(n int) rune {
+	if l.pos+n >= len(l.data) {
 		return runeEOF
 	}
 
-	return l.data[l.readPos]
+	return l.data[l.pos+n]
+}
+
+// peekTripleQuote reports whether the quote under the cursor opens a triple quoted block
+func (l *lexer) peekTripleQuote() bool {
+	return l.peekRuneAt(1) == l.char && l.peekRuneAt(2) == l.char
 }
 
 func (l *lexer) readComment() string {
@@ -172,10 +228,10 @@ func (l *lexer) readComment() string {
 	var buf bytes.Buffer
 
 	for {
-		curRune := l.data[l.pos]
+		curRune := l.char
 		peekRune := l.peekRune()
 
-		if peekRune == runeEOF {
+		if curRune == runeEOF {
 			break
 		} else if curRune == '\r' && peekRune != '\n' {
 			break
@@ -190,64 +246,382 @@ func (l *lexer) readComment() string {
 	return strings.TrimSpace(buf.String())
 }
 
-func (l *lexer) readQuotedString(terminator rune) (string, bool) {
-	var buf bytes.Buffer
+// readQuotedString reads a string wrapped in the quote character currently under the cursor
+//
+// tknValue strings will have their escape sequences decoded, all other types are kept literal with
+// the exception of an escaped terminator
+func (l *lexer) readQuotedString(tkn token, typ string) token {
+	var (
+		buf     bytes.Buffer
+		masked  []int
+		illegal *token
+	)
+
+	terminator := l.char
+	start := l.pos + 1
 
 	for {
-		curRune := l.data[l.pos]
-		peekRune := l.peekRune()
-		// if we dont find a closing quote then its an invalid string
-		if peekRune == runeEOF {
-			return "", false
+		l.readRune()
+
+		switch {
+		case l.char == runeEOF:
+			// if we dont find a closing quote then its an invalid string
+			return tkn.invalid("", "unterminated quote")
+		case l.char == terminator:
+			if illegal != nil {
+				return *illegal
+			}
+
+			return tkn.With(typ, buf.String()).masking(masked).written(string(l.data[start:l.pos]))
+		case l.char == '\\' && typ == tknValue:
+			// NB: we only report the first invalid sequence but keep reading until the closing quote
+			//     so that the rest of the string does not get lexed as garbage
+			if !l.readEscape(&buf, &masked) && illegal == nil {
+				end := min(l.pos+2, len(l.data))
+				bad := l.tkn().invalid(string(l.data[l.pos:end]), "invalid escape sequence")
+				illegal = &bad
+			}
+		case l.char == '\\' && l.peekRune() == terminator:
+			l.readRune()
+			buf.WriteRune(l.char)
+		default:
+			buf.WriteRune(l.char)
+		}
+	}
+}
+
+// readEscape decodes the escape sequence starting at the backslash under the cursor into buf
+//
+// The offset of any escaped $ is added to masked so that it will be kept literal during expansion
+func (l *lexer) readEscape(buf *bytes.Buffer, masked *[]int) bool {
+	switch l.peekRune() {
+	case '$':
+		l.readRune()
+		*masked = append(*masked, buf.Len())
+		buf.WriteRune(l.char)
+		return true
+	case '\'':
+		l.readRune()
+		buf.WriteRune(l.char)
+		return true
+	case '\r', '\n':
+		// as in a shell an escaped line break inside double quotes is a line continuation
+		if l.peekRune() == '\r' {
+			l.readRune()
+		}
+		if l.peekRune() == '\n' {
+			l.readRune()
+			return true
+		}
+	}
+
+	seq := l.escapeSequence()
+	value, multibyte, tail, err := strconv.UnquoteChar(seq, '"')
+	if err != nil {
+		return false
+	}
+
+	// the backslash itself is still under the cursor
+	for range utf8.RuneCountInString(seq) - utf8.RuneCountInString(tail) - 1 {
+		l.readRune()
+	}
+
+	if value == '$' {
+		*masked = append(*masked, buf.Len())
+	}
+
+	if value < utf8.RuneSelf || !multibyte {
+		buf.WriteByte(byte(value))
+	} else {
+		buf.WriteRune(value)
+	}
+
+	return true
+}
+
+// escapeSequence returns enough of the data following the cursor to hold the longest escape
+// sequence (\UXXXXXXXX)
+func (l *lexer) escapeSequence() string {
+	end := min(l.pos+10, len(l.data))
+	return string(l.data[l.pos:end])
+}
+
+// readTripleQuotedString reads a block wrapped in triple quotes that may span multiple lines
+//
+// A line break directly after the opening quotes is dropped as is the final line break if the
+// closing quotes sit on their own line, this allows the value to be written as an indented block
+func (l *lexer) readTripleQuotedString(tkn token, typ string) token {
+	var (
+		buf         bytes.Buffer
+		masked      []int
+		illegal     *token
+		lastNewline = -1
+	)
+
+	terminator := l.char
+
+	// skip the rest of the opening quotes
+	l.readRune()
+	l.readRune()
+
+	if l.peekRune() == '\r' && l.peekRuneAt(2) == '\n' {
+		l.readRune()
+	}
+	if l.peekRune() == '\n' {
+		l.readRune()
+	}
+
+	start := l.pos + 1
+
+	for {
+		l.readRune()
+
+		switch {
+		case l.char == runeEOF:
+			return tkn.invalid("", "unterminated quote")
+		case l.char == terminator && l.peekTripleQuote():
+			source := string(l.data[start:l.pos])
+			l.readRune()
+			l.readRune()
+
+			if illegal != nil {
+				return *illegal
+			}
+
+			str := buf.String()
+			if lastNewline >= 0 && strings.TrimLeft(str[lastNewline:], " \t\r\n") == "" {
+				str = strings.TrimSuffix(str[:lastNewline], "\r")
+
+				// NB: the source has no decoded escapes so its last line break is always the real one
+				i := strings.LastIndex(source, "\n")
+				source = strings.TrimSuffix(source[:i], "\r")
+			}
+
+			return tkn.With(typ, str).masking(masked).written(source)
+		case l.char == '\\' && typ == tknValue:
+			if !l.readEscape(&buf, &masked) && illegal == nil {
+				end := min(l.pos+2, len(l.data))
+				bad := l.tkn().invalid(string(l.data[l.pos:end]), "invalid escape sequence")
+				illegal = &bad
+			}
+		case l.char == '\n':
+			lastNewline = buf.Len()
+			buf.WriteRune(l.char)
+		default:
+			buf.WriteRune(l.char)
+		}
+	}
+}
+
+// peekHeredoc reports whether the << under the cursor opens a heredoc
+//
+// The delimiter must be followed by the end of the line or a comment, anything else is read as an
+// unquoted value so that values such as <<tag>> are still supported
+func (l *lexer) peekHeredoc() bool {
+	// skip the <<
+	n := 2
+	if l.peekRuneAt(n) == '-' {
+		n++
+	}
+
+	switch quote := l.peekRuneAt(n); {
+	case quote == '\'' || quote == '"':
+		n++
+		start := n
+
+		for l.peekRuneAt(n) != quote {
+			if char := l.peekRuneAt(n); char == '\n' || char == runeEOF {
+				return false
+			}
+			n++
+		}
+
+		if n == start {
+			return false
+		}
+		n++
+	case isIdentRune(quote):
+		n++
+		for isIdentRune(l.peekRuneAt(n), true) {
+			n++
 		}
+	default:
+		return false
+	}
+
+	for l.peekRuneAt(n) == ' ' || l.peekRuneAt(n) == '\t' {
+		n++
+	}
+
+	switch l.peekRuneAt(n) {
+	case '\n', '#', runeEOF:
+		return true
+	case '\r':
+		return l.peekRuneAt(n+1) == '\n'
+	}
+
+	return false
+}
+
+// readHeredoc reads a shell style <<EOF heredoc, peekHeredoc must have already confirmed that the
+// cursor is at the start of one
+//
+// The body runs from the line following the opening marker up until a line containing only the
+// delimiter. Quoting the delimiter (<<'EOF') produces a raw value and the <<- form will strip the
+// indentation common to all of the lines in the body as well as the closing delimiter
+func (l *lexer) readHeredoc(tkn token) token {
+	var (
+		delimiter string
+		typ       = tknValue
+		strip     bool
+	)
+
+	// skip the <<
+	l.readRune()
+
+	if l.peekRune() == '-' {
+		strip = true
+		l.readRune()
+	}
+
+	switch quote := l.peekRune(); quote {
+	case '\'', '"':
+		typ = tknRawValue
+		l.readRune()
+
+		var buf bytes.Buffer
+		for l.peekRune() != quote {
+			l.readRune()
+			buf.WriteRune(l.char)
+		}
+
+		l.readRune()
+		delimiter = buf.String()
+	default:
+		l.readRune()
+		delimiter = l.readIdentifier()
+	}
+
+	// the remainder of the opening line can only be a comment
+	l.readLine()
 
-		if curRune != '\\' || peekRune != terminator {
-			buf.WriteRune(curRune)
+	// NB: if the heredoc is never closed lexing resumes from the end of the opening line so that
+	//     only this entry is lost
+	opening := *l
+
+	var lines []string
+	for {
+		if l.peekRune() == runeEOF {
+			*l = opening
+			return tkn.invalid("", "unterminated heredoc")
 		}
 
+		// move onto the line break
 		l.readRune()
-		if peekRune == terminator && curRune != '\\' {
+
+		line := l.readLine()
+		if line == delimiter || strip && strings.TrimLeft(line, " \t") == delimiter {
 			break
 		}
+
+		lines = append(lines, line)
 	}
 
-	// skip final "
-	if l.peekRune() == terminator {
+	if strip {
+		lines = stripIndent(lines)
+	}
+
+	return tkn.With(typ, strings.Join(lines, "\n"))
+}
+
+// readLine reads the remainder of the current line without consuming the line break
+//
+// a trailing \r from a \r\n pair is not included in the result
+func (l *lexer) readLine() string {
+	var buf bytes.Buffer
+
+	for l.peekRune() != '\n' && l.peekRune() != runeEOF {
 		l.readRune()
+		buf.WriteRune(l.char)
 	}
 
-	if buf.Len() < 2 {
-		return "", true
+	return strings.TrimSuffix(buf.String(), "\r")
+}
+
+// stripIndent removes the leading whitespace common to all non blank lines
+func stripIndent(lines []string) []string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
 	}
 
-	// dont include the " on each side in the strings value
-	return buf.String()[1:], true
+	stripped := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) < indent {
+			stripped[i] = strings.TrimLeft(line, " \t")
+		} else if indent > 0 {
+			stripped[i] = line[indent:]
+		} else {
+			stripped[i] = line
+		}
+	}
+
+	return stripped
 }
 
-func (l *lexer) readUnquotedString() string {
+// readUnquotedString reads a value up until the end of the line or the start of an inline comment
+//
+// A backslash at the end of a line will continue the value onto the next line, any leading
+// whitespace on the continuation line is dropped
+func (l *lexer) readUnquotedString(tkn token) token {
 	var buf bytes.Buffer
 
+	start := l.pos
+
 	for {
-		curRune := l.data[l.pos]
+		curRune := l.char
 		peekRune := l.peekRune()
 
-		if peekRune == runeEOF {
+		if curRune == runeEOF || curRune == '\n' {
 			break
 		}
 
-		if curRune == '\n' {
+		if unicode.IsSpace(curRune) && peekRune == '#' {
 			break
 		}
 
-		if unicode.IsSpace(curRune) && peekRune == '#' {
-			break
+		if curRune == '\\' && (peekRune == '\n' || peekRune == '\r' && l.peekRuneAt(2) == '\n') {
+			l.skipLineContinuation()
+			continue
 		}
 
 		buf.WriteRune(curRune)
 		l.readRune()
 	}
 
-	return strings.TrimSpace(buf.String())
+	source := strings.TrimSpace(string(l.data[start:min(l.pos, len(l.data))]))
+
+	return tkn.With(tknValue, strings.TrimSpace(buf.String())).written(source)
+}
+
+// skipLineContinuation moves the cursor from a trailing backslash to the first non blank
+// character on the following line
+func (l *lexer) skipLineContinuation() {
+	for l.char != '\n' {
+		l.readRune()
+	}
+	l.readRune()
+
+	for l.char == ' ' || l.char == '\t' {
+		l.readRune()
+	}
 }
 
 func (l *lexer) readIdentifier() string {
@@ -265,9 +639,11 @@ func (l *lexer) peekIdentifier() string {
 	readPos := l.readPos
 	linePos := l.linePos
 	line := l.line
+	char := l.char
 
 	ident := l.readIdentifier()
 
+	l.char = char
 	l.pos = pos
 	l.readPos = readPos
 	l.line = line
@@ -276,6 +652,16 @@ func (l *lexer) peekIdentifier() string {
 	return ident
 }
 
+// sourceLine returns the full text of the 0-based line from the lexers input
+func (l *lexer) sourceLine(line int) string {
+	lines := strings.Split(string(l.data), "\n")
+	if line < 0 || line >= len(lines) {
+		return ""
+	}
+
+	return strings.TrimSuffix(lines[line], "\r")
+}
+
 func isIdentRune(char rune, subsequent ...bool) bool {
 	// numbers are allowed in idents but not as the first character
 	if len(subsequent) > 0 && subsequent[0] && char >= '0' && char <= '9' {
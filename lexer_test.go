@@ -26,11 +26,11 @@ var lexNextTokenTestCases = []struct {
 			{Line: 1, Pos: 16, Type: tknEOL, Literal: ""},
 			{Line: 2, Pos: 1, Type: tknIdentifier, Literal: "SINGLE_QUOTE"},
 			{Line: 2, Pos: 13, Type: tknEquals, Literal: "="},
-			{Line: 2, Pos: 14, Type: tknRawValue, Literal: "single quote"},
+			{Line: 2, Pos: 14, Type: tknRawValue, Literal: "single quote", Quote: QuoteSingle},
 			{Line: 2, Pos: 28, Type: tknEOL, Literal: ""},
 			{Line: 3, Pos: 1, Type: tknIdentifier, Literal: "DOUBLE_QUOTE"},
 			{Line: 3, Pos: 13, Type: tknEquals, Literal: "="},
-			{Line: 3, Pos: 14, Type: tknValue, Literal: "double quote"},
+			{Line: 3, Pos: 14, Type: tknValue, Literal: "double quote", Quote: QuoteDouble},
 			{Line: 3, Pos: 28, Type: tknEOL, Literal: ""},
 			{Line: 4, Pos: 1, Type: tknIdentifier, Literal: "UNQUOTED"},
 			{Line: 4, Pos: 9, Type: tknEquals, Literal: "="},
@@ -48,9 +48,9 @@ var lexNextTokenTestCases = []struct {
 			{Line: 6, Pos: 45, Type: tknEOL, Literal: ""},
 			{Line: 7, Pos: 1, Type: tknIdentifier, Literal: "MULTI_LINE"},
 			{Line: 7, Pos: 11, Type: tknEquals, Literal: "="},
-			{Line: 7, Pos: 12, Type: tknValue, Literal: "this\none has multiple\nlines"},
-			{Line: 7, Pos: 41, Type: tknEOL, Literal: ""},
-			{Line: 8, Pos: 1, Type: tknEOF, Literal: ""},
+			{Line: 7, Pos: 12, Type: tknValue, Literal: "this\none has multiple\nlines", Quote: QuoteDouble},
+			{Line: 9, Pos: 7, Type: tknEOL, Literal: ""},
+			{Line: 10, Pos: 1, Type: tknEOF, Literal: ""},
 		},
 	},
 	{
@@ -71,7 +71,7 @@ var lexNextTokenTestCases = []struct {
 			{Line: 3, Pos: 6, Type: tknEquals, Literal: "="},
 			{Line: 3, Pos: 7, Type: tknEOL, Literal: ""},
 			{Line: 4, Pos: 1, Type: tknEquals, Literal: "="},
-			{Line: 4, Pos: 2, Type: tknRawValue, Literal: "single quote"},
+			{Line: 4, Pos: 2, Type: tknRawValue, Literal: "single quote", Quote: QuoteSingle},
 			{Line: 4, Pos: 16, Type: tknEOL, Literal: ""},
 			{Line: 5, Pos: 1, Type: tknIdentifier, Literal: "EMPTY_WITH_COMMENT"},
 			{Line: 5, Pos: 19, Type: tknEquals, Literal: "="},
@@ -89,7 +89,7 @@ var lexNextTokenTestCases = []struct {
 		[]token{
 			{Line: 0, Pos: 1, Type: "IDENT", Literal: "VALUE"},
 			{Line: 0, Pos: 6, Type: "EQUALS", Literal: "="},
-			{Line: 0, Pos: 7, Type: "VALUE", Literal: "inserted"},
+			{Line: 0, Pos: 7, Type: "VALUE", Literal: "inserted", Quote: QuoteDouble},
 			{Line: 0, Pos: 17, Type: "EOL", Literal: ""},
 			{Line: 1, Pos: 1, Type: "IDENT", Literal: "REPLACE"},
 			{Line: 1, Pos: 8, Type: "EQUALS", Literal: "="},
@@ -97,31 +97,123 @@ var lexNextTokenTestCases = []struct {
 			{Line: 1, Pos: 17, Type: "EOL", Literal: ""},
 			{Line: 2, Pos: 1, Type: "IDENT", Literal: "REPLACE_SINGLE"},
 			{Line: 2, Pos: 15, Type: "EQUALS", Literal: "="},
-			{Line: 2, Pos: 16, Type: "RAW_VALUE", Literal: "${VALUE}"},
+			{Line: 2, Pos: 16, Type: "RAW_VALUE", Literal: "${VALUE}", Quote: QuoteSingle},
 			{Line: 2, Pos: 26, Type: "EOL", Literal: ""},
 			{Line: 3, Pos: 1, Type: "IDENT", Literal: "REPLACE_DOUBLE"},
 			{Line: 3, Pos: 15, Type: "EQUALS", Literal: "="},
-			{Line: 3, Pos: 16, Type: "VALUE", Literal: "${VALUE}"},
+			{Line: 3, Pos: 16, Type: "VALUE", Literal: "${VALUE}", Quote: QuoteDouble},
 			{Line: 3, Pos: 26, Type: "EOL", Literal: ""},
 			{Line: 4, Pos: 1, Type: "IDENT", Literal: "REPLACE_PARTIAL"},
 			{Line: 4, Pos: 16, Type: "EQUALS", Literal: "="},
-			{Line: 4, Pos: 17, Type: "VALUE", Literal: "partialy ${VALUE} value"},
+			{Line: 4, Pos: 17, Type: "VALUE", Literal: "partialy ${VALUE} value", Quote: QuoteDouble},
 			{Line: 4, Pos: 42, Type: "EOL", Literal: ""},
 			{Line: 5, Pos: 1, Type: "IDENT", Literal: "REPLACE_ESCAPED"},
 			{Line: 5, Pos: 16, Type: "EQUALS", Literal: "="},
-			{Line: 5, Pos: 17, Type: "VALUE", Literal: "partialy \\${VALUE} value"},
+			{Line: 5, Pos: 17, Type: "VALUE", Literal: "partialy ${VALUE} value", Quote: QuoteDouble, Masked: []int{9}, Source: "partialy \\${VALUE} value"},
 			{Line: 5, Pos: 43, Type: "EOL", Literal: ""},
 			{Line: 6, Pos: 1, Type: "IDENT", Literal: "REPLACE_FROM_BASIC"},
 			{Line: 6, Pos: 19, Type: "EQUALS", Literal: "="},
-			{Line: 6, Pos: 20, Type: "VALUE", Literal: "${HASH_WITH_COMMENT}"},
+			{Line: 6, Pos: 20, Type: "VALUE", Literal: "${HASH_WITH_COMMENT}", Quote: QuoteDouble},
 			{Line: 6, Pos: 42, Type: "EOL", Literal: ""},
 			{Line: 7, Pos: 1, Type: "IDENT", Literal: "REPLACE_FROM_BROKEN"},
 			{Line: 7, Pos: 20, Type: "EQUALS", Literal: "="},
-			{Line: 7, Pos: 21, Type: "VALUE", Literal: "${EMPTY}"},
+			{Line: 7, Pos: 21, Type: "VALUE", Literal: "${EMPTY}", Quote: QuoteDouble},
 			{Line: 7, Pos: 31, Type: "EOL", Literal: ""},
 			{Line: 8, Pos: 1, Type: "EOF", Literal: ""},
 		},
 	},
+	{
+		"fixtures/invalid_escape.env",
+		[]token{
+			{Line: 0, Pos: 1, Type: tknIdentifier, Literal: "VALID"},
+			{Line: 0, Pos: 6, Type: tknEquals, Literal: "="},
+			{Line: 0, Pos: 7, Type: tknValue, Literal: "valid", Quote: QuoteDouble},
+			{Line: 0, Pos: 14, Type: tknEOL, Literal: ""},
+			{Line: 1, Pos: 1, Type: tknIdentifier, Literal: "INVALID"},
+			{Line: 1, Pos: 8, Type: tknEquals, Literal: "="},
+			{Line: 1, Pos: 14, Type: tknIllegal, Literal: "\\q", Reason: "invalid escape sequence"},
+			{Line: 1, Pos: 24, Type: tknEOL, Literal: ""},
+			{Line: 2, Pos: 1, Type: tknEOF, Literal: ""},
+		},
+	},
+	{
+		"fixtures/multiline.env",
+		[]token{
+			{Line: 0, Pos: 1, Type: tknIdentifier, Literal: "TRIPLE_DOUBLE"},
+			{Line: 0, Pos: 14, Type: tknEquals, Literal: "="},
+			{Line: 0, Pos: 15, Type: tknValue, Literal: "first ${VALUE}\n\tsecond\tline", Quote: QuoteDouble, Source: "first ${VALUE}\n\tsecond\\tline"},
+			{Line: 3, Pos: 4, Type: tknEOL, Literal: ""},
+			{Line: 4, Pos: 1, Type: tknIdentifier, Literal: "TRIPLE_SINGLE"},
+			{Line: 4, Pos: 14, Type: tknEquals, Literal: "="},
+			{Line: 4, Pos: 15, Type: tknRawValue, Literal: "raw ${VALUE}\\n", Quote: QuoteSingle},
+			{Line: 6, Pos: 4, Type: tknEOL, Literal: ""},
+			{Line: 7, Pos: 1, Type: tknIdentifier, Literal: "INLINE_TRIPLE"},
+			{Line: 7, Pos: 14, Type: tknEquals, Literal: "="},
+			{Line: 7, Pos: 15, Type: tknValue, Literal: "one \"quoted\" line", Quote: QuoteDouble},
+			{Line: 7, Pos: 38, Type: tknEOL, Literal: ""},
+			{Line: 8, Pos: 1, Type: tknIdentifier, Literal: "HEREDOC"},
+			{Line: 8, Pos: 8, Type: tknEquals, Literal: "="},
+			{Line: 8, Pos: 9, Type: tknValue, Literal: "SELECT *\n  FROM table;", Quote: QuoteHeredoc},
+			{Line: 11, Pos: 4, Type: tknEOL, Literal: ""},
+			{Line: 12, Pos: 1, Type: tknIdentifier, Literal: "HEREDOC_RAW"},
+			{Line: 12, Pos: 12, Type: tknEquals, Literal: "="},
+			{Line: 12, Pos: 13, Type: tknRawValue, Literal: "${VALUE}", Quote: QuoteHeredoc},
+			{Line: 14, Pos: 4, Type: tknEOL, Literal: ""},
+			{Line: 15, Pos: 1, Type: tknIdentifier, Literal: "HEREDOC_INDENTED"},
+			{Line: 15, Pos: 17, Type: tknEquals, Literal: "="},
+			{Line: 15, Pos: 18, Type: tknValue, Literal: "-----BEGIN KEY-----\n  abc\n-----END KEY-----", Quote: QuoteHeredoc},
+			{Line: 19, Pos: 8, Type: tknEOL, Literal: ""},
+			{Line: 20, Pos: 1, Type: tknIdentifier, Literal: "AFTER"},
+			{Line: 20, Pos: 6, Type: tknEquals, Literal: "="},
+			{Line: 20, Pos: 7, Type: tknValue, Literal: "value"},
+			{Line: 20, Pos: 12, Type: tknEOL, Literal: ""},
+			{Line: 21, Pos: 1, Type: tknEOF, Literal: ""},
+		},
+	},
+	{
+		"fixtures/continuation.env",
+		[]token{
+			{Line: 0, Pos: 1, Type: tknIdentifier, Literal: "HOSTS"},
+			{Line: 0, Pos: 6, Type: tknEquals, Literal: "="},
+			{Line: 0, Pos: 7, Type: tknValue, Literal: "one.example.com,two.example.com,three.example.com", Source: "one.example.com,\\\n    two.example.com,\\\n\tthree.example.com"},
+			{Line: 2, Pos: 20, Type: tknComment, Literal: "hosts"},
+			{Line: 2, Pos: 27, Type: tknEOL, Literal: ""},
+			{Line: 3, Pos: 1, Type: tknIdentifier, Literal: "CRLF"},
+			{Line: 3, Pos: 5, Type: tknEquals, Literal: "="},
+			{Line: 3, Pos: 6, Type: tknValue, Literal: "first second", Source: "first \\\r\n  second"},
+			{Line: 4, Pos: 10, Type: tknEOL, Literal: ""},
+			{Line: 5, Pos: 1, Type: tknIdentifier, Literal: "QUOTED"},
+			{Line: 5, Pos: 7, Type: tknEquals, Literal: "="},
+			{Line: 5, Pos: 8, Type: tknValue, Literal: "quoted continuation", Quote: QuoteDouble, Source: "quoted \\\ncontinuation"},
+			{Line: 6, Pos: 14, Type: tknEOL, Literal: ""},
+			{Line: 7, Pos: 1, Type: tknIdentifier, Literal: "LAST"},
+			{Line: 7, Pos: 5, Type: tknEquals, Literal: "="},
+			{Line: 7, Pos: 6, Type: tknValue, Literal: "no trailing newline"},
+			{Line: 7, Pos: 25, Type: tknEOF, Literal: ""},
+		},
+	},
+	{
+		"fixtures/backtick.env",
+		[]token{
+			{Line: 0, Pos: 1, Type: tknIdentifier, Literal: "VALUE"},
+			{Line: 0, Pos: 6, Type: tknEquals, Literal: "="},
+			{Line: 0, Pos: 7, Type: tknValue, Literal: "inserted"},
+			{Line: 0, Pos: 15, Type: tknEOL, Literal: ""},
+			{Line: 1, Pos: 1, Type: tknIdentifier, Literal: "BACKTICK"},
+			{Line: 1, Pos: 9, Type: tknEquals, Literal: "="},
+			{Line: 1, Pos: 10, Type: tknBacktickValue, Literal: "it's \"mixed\"", Quote: QuoteBacktick},
+			{Line: 1, Pos: 24, Type: tknEOL, Literal: ""},
+			{Line: 2, Pos: 1, Type: tknIdentifier, Literal: "BACKTICK_EXPAND"},
+			{Line: 2, Pos: 16, Type: tknEquals, Literal: "="},
+			{Line: 2, Pos: 17, Type: tknBacktickValue, Literal: "${VALUE}\\n", Quote: QuoteBacktick},
+			{Line: 2, Pos: 29, Type: tknEOL, Literal: ""},
+			{Line: 3, Pos: 1, Type: tknIdentifier, Literal: "BACKTICK_ESCAPED"},
+			{Line: 3, Pos: 17, Type: tknEquals, Literal: "="},
+			{Line: 3, Pos: 18, Type: tknBacktickValue, Literal: "\\${VALUE} ` tick", Quote: QuoteBacktick, Source: "\\${VALUE} \\` tick"},
+			{Line: 3, Pos: 37, Type: tknEOL, Literal: ""},
+			{Line: 4, Pos: 1, Type: tknEOF, Literal: ""},
+		},
+	},
 }
 
 func TestLexerNextToken(t *testing.T) {
@@ -0,0 +1,443 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Option configures the behaviour of LoadWith
+type Option func(*options)
+
+type options struct {
+	files     []envFile
+	overwrite bool
+	strict    bool
+	expand    bool
+	prefix    string
+	patterns  []string
+	strip     string
+	add       string
+	readFile  readFileFunc
+	fsys      fs.FS
+	search    SearchMode
+	cascade   bool
+	envName   string
+	result    *LoadResult
+	redacted  []string
+
+	// defined holds the rewritten keys that have been defined by the files in this load
+	defined map[string]struct{}
+
+	// track is set by WithProvenance when loading into the os.environment, defs holds the
+	// definitions to be added to the provenance registry
+	track bool
+	defs  []Definition
+
+	protect       bool
+	protectAction ProtectAction
+	protected     []string
+	allowed       []string
+
+	// env is the Environment that envars are assigned to
+	env Environment
+	// resolve is the Environment used to look up variables during expansion, if not set then env
+	// will be used
+	resolve Environment
+}
+
+// envFile is a single file in the load order
+type envFile struct {
+	path     string
+	optional bool
+	// reader is set if the data should be read from it rather than the file at path
+	reader io.Reader
+}
+
+// newEnvFile creates an envFile for path, a trailing ? marks the file as optional
+func newEnvFile(path string) envFile {
+	if trimmed, ok := strings.CutSuffix(path, "?"); ok {
+		return envFile{path: trimmed, optional: true}
+	}
+
+	return envFile{path: path}
+}
+
+// WithFiles adds files to the list to be loaded, files are loaded in the order they are provided.
+// If no files are provided it will default to loading .env
+//
+// Files are required unless their path ends with a ? (.env.local?), if a required file cannot be
+// read then the load will fail
+func WithFiles(filepaths ...string) Option {
+	return func(o *options) {
+		for _, path := range filepaths {
+			o.files = append(o.files, newEnvFile(path))
+		}
+	}
+}
+
+// WithOptionalFiles adds files to the list to be loaded, files are loaded in the order they are
+// provided.
+//
+// Optional files will be skipped if they do not exist, the skipped files are recorded in the
+// LoadResult
+func WithOptionalFiles(filepaths ...string) Option {
+	return func(o *options) {
+		for _, path := range filepaths {
+			o.files = append(o.files, envFile{path: path, optional: true})
+		}
+	}
+}
+
+// WithReader adds the env data read from r to the list to be loaded, it is loaded in the same
+// position as it was provided relative to any files.
+//
+// The optional name will be used in place of a file path in any errors and in the LoadResult
+func WithReader(r io.Reader, name ...string) Option {
+	return func(o *options) {
+		file := envFile{reader: r}
+		if len(name) > 0 {
+			file.path = name[0]
+		}
+
+		o.files = append(o.files, file)
+	}
+}
+
+// WithOverride will replace any variables that already exist in the environment, the last file to
+// define a variable will win
+func WithOverride() Option {
+	return func(o *options) {
+		o.overwrite = true
+	}
+}
+
+// WithStrict will stop loading at the first file that contains invalid syntax, all of the files
+// are still parsed so that the returned error can report every syntax error found
+//
+// A required file that cannot be read stops the parsing, its error is joined after any syntax
+// errors already found
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithExpansion toggles ${VAR} expansion of values, expansion is enabled by default
+func WithExpansion(enabled bool) Option {
+	return func(o *options) {
+		o.expand = enabled
+	}
+}
+
+// WithPrefix will only load variables whose key starts with prefix
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithKeyPattern will only load variables whose key matches one of the glob patterns (BILLING_*),
+// patterns use the syntax of path.Match.
+//
+// Calling WithKeyPattern multiple times will add to the list of patterns
+func WithKeyPattern(patterns ...string) Option {
+	return func(o *options) {
+		o.patterns = append(o.patterns, patterns...)
+	}
+}
+
+// WithStripPrefix removes prefix from the start of any keys that have it before they are set,
+// keys are filtered before the prefix is removed
+func WithStripPrefix(prefix string) Option {
+	return func(o *options) {
+		o.strip = prefix
+	}
+}
+
+// WithAddPrefix adds prefix to the start of every key before it is set, this is applied after
+// WithStripPrefix
+func WithAddPrefix(prefix string) Option {
+	return func(o *options) {
+		o.add = prefix
+	}
+}
+
+// WithEnvironment loads variables into env rather than the os.environment, ${VAR} expansion will
+// also resolve variables from env
+func WithEnvironment(env Environment) Option {
+	return func(o *options) {
+		o.env = env
+	}
+}
+
+// WithFS reads files from fsys rather than the os file system, paths must follow the rules of
+// fs.ValidPath
+func WithFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.fsys = fsys
+		o.readFile = readFileFS(fsys)
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		expand:   true,
+		readFile: os.ReadFile,
+		env:      OSEnv{},
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.resolve == nil {
+		o.resolve = o.env
+	}
+
+	if _, ok := o.env.(OSEnv); !ok {
+		o.track = false
+	}
+
+	return o
+}
+
+// LoadWith loads .env files into the environment as configured by the provided options.
+//
+// By default it behaves the same as Load:
+// - .env will be loaded from the current working directory if no files are provided
+// - existing variables will not be replaced
+// - lines containing invalid syntax will be skipped
+// - values will be expanded
+// - variables will be loaded into the os.environment
+func LoadWith(opts ...Option) error {
+	o := newOptions(opts...)
+	defer o.publish()
+
+	return o.load()
+}
+
+// paths returns the files to load followed by the cascade if enabled, if neither were provided it
+// falls back to a required .env
+//
+// If a search mode has been set then the files found in parent directories will also be included
+func (o *options) paths() ([]envFile, error) {
+	files := o.files
+
+	if o.cascade {
+		cascade, err := o.cascadeFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(slices.Clip(files), cascade...)
+	} else if len(files) == 0 {
+		files = []envFile{{path: ".env"}}
+	}
+
+	return o.searchParents(files), nil
+}
+
+func (o *options) load() error {
+	var errs []error
+
+	for _, pattern := range slices.Concat(o.patterns, o.protected, o.allowed, o.redacted) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+		}
+	}
+
+	files, err := o.paths()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		p, err := o.parse(file)
+		if err != nil {
+			if file.optional && errors.Is(err, fs.ErrNotExist) {
+				o.skipped(file)
+				continue
+			}
+
+			if o.strict {
+				return errors.Join(append(errs, err)...)
+			}
+
+			return err
+		}
+
+		if !o.strict {
+			pairs, warnings := p.ParseWithWarnings()
+			o.warned(warnings)
+
+			if err := o.assign(pairs); err != nil {
+				return err
+			}
+
+			o.loaded(file)
+			continue
+		}
+
+		pairs, parseErrs := p.parseStrict(true)
+		if len(parseErrs) > 0 {
+			errs = append(errs, parseErrs...)
+			continue
+		}
+
+		// NB: once a file has failed we only continue parsing to report any further errors
+		if len(errs) > 0 {
+			continue
+		}
+
+		if err := o.assign(pairs); err != nil {
+			return err
+		}
+
+		o.loaded(file)
+	}
+
+	return errors.Join(errs...)
+}
+
+// warned records the warnings produced by a lenient parse in the result
+func (o *options) warned(warnings []ParseWarning) {
+	if o.result != nil {
+		o.result.Warnings = append(o.result.Warnings, warnings...)
+	}
+}
+
+// parse reads and parses a single file in the load order
+func (o *options) parse(file envFile) (*Parser, error) {
+	if file.reader != nil {
+		return ParseReader(file.reader, file.path)
+	}
+
+	return parseFile(o.readFile, file.path)
+}
+
+// loaded records a file that has been loaded in the result
+func (o *options) loaded(file envFile) {
+	if o.result != nil {
+		o.result.Files = append(o.result.Files, file.path)
+	}
+}
+
+// skipped records a missing optional file in the result
+func (o *options) skipped(file envFile) {
+	if o.result != nil {
+		o.result.Skipped = append(o.result.Skipped, file.path)
+	}
+}
+
+func (o *options) assign(pairs []ParseEntry) error {
+	for _, v := range pairs {
+		key, ok := o.rewrite(v.Key)
+		if !ok {
+			continue
+		}
+
+		val := v.Value
+		if !v.Raw && val != "" {
+			if o.expand {
+				val = v.expand(o.expansion)
+			} else {
+				val = v.unescape()
+			}
+		}
+
+		prev, exists := o.env.Lookup(key)
+		res := KeyResult{
+			Key:      key,
+			File:     v.File,
+			Line:     v.Line,
+			OldValue: prev,
+			Existed:  exists,
+			NewValue: val,
+		}
+
+		// NB: existing keys that would not be replaced are skipped before the protection check as
+		//     they were never going to be set
+		if exists && !o.overwrite {
+			o.define(key)
+
+			res.Outcome = KeySkipped
+			o.record(res, v)
+			continue
+		}
+
+		if o.isProtected(key) {
+			res.Outcome = KeyProtected
+			o.record(res, v)
+
+			if o.result != nil {
+				o.result.Protected = append(o.result.Protected, key)
+			}
+
+			if o.protectAction == ProtectError {
+				return &ProtectedKeyError{File: v.File, Line: v.Line, Column: v.Column, Key: key}
+			}
+
+			continue
+		}
+
+		o.define(key)
+
+		if err := o.env.Set(key, val); err != nil {
+			return err
+		}
+
+		res.Outcome = KeySet
+		if exists {
+			res.Outcome = KeyOverridden
+		}
+
+		o.record(res, v)
+	}
+
+	return nil
+}
+
+// define marks the rewritten key as defined by this load
+func (o *options) define(key string) {
+	if o.defined == nil {
+		o.defined = make(map[string]struct{})
+	}
+
+	o.defined[key] = struct{}{}
+}
+
+// rewrite applies the key filters and prefix rewriting to key, it returns false if the key should
+// not be loaded
+func (o *options) rewrite(key string) (string, bool) {
+	if !strings.HasPrefix(key, o.prefix) {
+		return "", false
+	}
+
+	if len(o.patterns) > 0 && !matchAny(o.patterns, key) {
+		return "", false
+	}
+
+	return o.add + strings.TrimPrefix(key, o.strip), true
+}
+
+// expansion looks up variables during expansion
+//
+// Variables loaded from the files may have been rewritten so if the rewritten key has been defined
+// by this load it is used in place of the key as it appears in the file
+func (o *options) expansion(key string) string {
+	if rewritten, ok := o.rewrite(key); ok && rewritten != key {
+		if _, ok := o.defined[rewritten]; ok {
+			val, _ := o.resolve.Lookup(rewritten)
+			return val
+		}
+	}
+
+	val, _ := o.resolve.Lookup(key)
+	return val
+}
@@ -0,0 +1,276 @@
+package dotenv
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+var loadWithTestCases = []struct {
+	name          string
+	env           map[string]string
+	opts          []Option
+	expected      map[string]string
+	expectedError error
+}{
+	{
+		name: "defaults",
+		env:  map[string]string{"VALUE": "existing"},
+		opts: []Option{WithFiles("fixtures/replacement.env")},
+		expected: map[string]string{
+			"VALUE":               "existing",
+			"REPLACE":             "existing",
+			"REPLACE_SINGLE":      "${VALUE}",
+			"REPLACE_DOUBLE":      "existing",
+			"REPLACE_PARTIAL":     "partialy existing value",
+			"REPLACE_ESCAPED":     "partialy ${VALUE} value",
+			"REPLACE_FROM_BASIC":  "",
+			"REPLACE_FROM_BROKEN": "",
+		},
+	},
+	{
+		name: "override",
+		env:  map[string]string{"VALUE": "existing"},
+		opts: []Option{WithFiles("fixtures/replacement.env"), WithOverride(), WithPrefix("VALUE")},
+		expected: map[string]string{
+			"VALUE": "inserted",
+		},
+	},
+	{
+		name: "expansion disabled",
+		opts: []Option{WithFiles("fixtures/replacement.env"), WithExpansion(false), WithPrefix("REPLACE_")},
+		expected: map[string]string{
+			"REPLACE_SINGLE":      "${VALUE}",
+			"REPLACE_DOUBLE":      "${VALUE}",
+			"REPLACE_PARTIAL":     "partialy ${VALUE} value",
+			"REPLACE_ESCAPED":     "partialy ${VALUE} value",
+			"REPLACE_FROM_BASIC":  "${HASH_WITH_COMMENT}",
+			"REPLACE_FROM_BROKEN": "${EMPTY}",
+		},
+	},
+	{
+		name: "expansion disabled escapes",
+		opts: []Option{WithFiles("fixtures/escapes.env"), WithExpansion(false), WithKeyPattern("BACKSLASH", "DOLLAR")},
+		expected: map[string]string{
+			"BACKSLASH": "C:\\path",
+			"DOLLAR":    "${VALUE}",
+		},
+	},
+	{
+		name:     "prefix",
+		opts:     []Option{WithFiles("fixtures/basic.env"), WithPrefix("UN")},
+		expected: map[string]string{"UNEXPORTED": "data", "UNQUOTED": "unquoted data"},
+	},
+	{
+		name: "optional files",
+		opts: []Option{
+			WithOptionalFiles("fixtures/missing.env"),
+			WithFiles("fixtures/basic.env"),
+			WithOptionalFiles("fixtures/missing.local.env"),
+			WithPrefix("UN"),
+		},
+		expected: map[string]string{"UNEXPORTED": "data", "UNQUOTED": "unquoted data"},
+	},
+	{
+		name:          "missing required file",
+		opts:          []Option{WithFiles("fixtures/missing.env", "fixtures/basic.env")},
+		expected:      map[string]string{},
+		expectedError: fs.ErrNotExist,
+	},
+	{
+		name:          "strict",
+		opts:          []Option{WithFiles("fixtures/basic.env", "fixtures/broken.env"), WithStrict(), WithPrefix("UN")},
+		expected:      map[string]string{"UNEXPORTED": "data", "UNQUOTED": "unquoted data"},
+		expectedError: &SyntaxError{},
+	},
+	{
+		name: "key pattern",
+		env:  map[string]string{"USER": "app"},
+		opts: []Option{WithFiles("fixtures/prefix.env"), WithKeyPattern("BILLING_*")},
+		expected: map[string]string{
+			"USER":            "app",
+			"BILLING_DB_HOST": "db.local",
+			"BILLING_DB_URL":  "postgres://db.local/billing",
+			"BILLING_DB_USER": "app",
+		},
+	},
+	{
+		name: "multiple key patterns",
+		opts: []Option{WithFiles("fixtures/prefix.env"), WithKeyPattern("*_DB_URL"), WithKeyPattern("*_HOST")},
+		expected: map[string]string{
+			"BILLING_DB_HOST": "db.local",
+			"BILLING_DB_URL":  "postgres://db.local/billing",
+			"SHIPPING_DB_URL": "postgres://shipping",
+		},
+	},
+	{
+		name: "strip prefix",
+		env:  map[string]string{"USER": "app"},
+		opts: []Option{WithFiles("fixtures/prefix.env"), WithPrefix("BILLING_"), WithStripPrefix("BILLING_")},
+		expected: map[string]string{
+			"USER":    "app",
+			"DB_HOST": "db.local",
+			"DB_URL":  "postgres://db.local/billing",
+			"DB_USER": "app",
+		},
+	},
+	{
+		name: "add prefix",
+		opts: []Option{WithFiles("fixtures/prefix.env"), WithKeyPattern("SHIPPING_*"), WithAddPrefix("APP_")},
+		expected: map[string]string{
+			"APP_SHIPPING_DB_URL": "postgres://shipping",
+		},
+	},
+	{
+		name: "add prefix ignores unrelated variables",
+		env:  map[string]string{"HOME": "/home/app", "APP_HOME": "/opt/unrelated"},
+		opts: []Option{
+			WithFS(fstest.MapFS{".env": &fstest.MapFile{Data: []byte(`CACHE="${HOME}/.cache"`)}}),
+			WithAddPrefix("APP_"),
+		},
+		expected: map[string]string{
+			"HOME":      "/home/app",
+			"APP_HOME":  "/opt/unrelated",
+			"APP_CACHE": "/home/app/.cache",
+		},
+	},
+	{
+		name: "replace prefix",
+		opts: []Option{WithFiles("fixtures/prefix.env"), WithStripPrefix("BILLING_"), WithAddPrefix("PAY_")},
+		expected: map[string]string{
+			"PAY_DB_HOST":         "db.local",
+			"PAY_DB_URL":          "postgres://db.local/billing",
+			"PAY_DB_USER":         "",
+			"PAY_SHIPPING_DB_URL": "postgres://shipping",
+		},
+	},
+	{
+		name: "fs",
+		opts: []Option{
+			WithFS(fstest.MapFS{".env": &fstest.MapFile{Data: []byte("FROM_FS=default")}}),
+		},
+		expected: map[string]string{"FROM_FS": "default"},
+	},
+}
+
+func TestLoadWith(t *testing.T) {
+	for _, tc := range loadWithTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := NewMapEnv(tc.env)
+
+			err := LoadWith(append(tc.opts, WithEnvironment(env))...)
+			switch target := tc.expectedError.(type) {
+			case nil:
+				require.Nil(t, err)
+			case *SyntaxError:
+				require.ErrorAs(t, err, &target)
+			default:
+				require.ErrorIs(t, err, tc.expectedError)
+			}
+
+			require.Equal(t, tc.expected, env.Map())
+		})
+	}
+}
+
+func TestLoadWithDefaultEnvironment(t *testing.T) {
+	os.Clearenv()
+
+	err := LoadWith(WithFiles("fixtures/basic.env"), WithPrefix("UN"))
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"UNEXPORTED=data", "UNQUOTED=unquoted data"}, os.Environ())
+}
+
+func TestLoadWithOptionalSuffix(t *testing.T) {
+	var result LoadResult
+	env := NewMapEnv(nil)
+
+	err := LoadWith(
+		WithFiles("fixtures/missing.env?", "fixtures/basic.env?", "fixtures/missing.local.env?"),
+		WithPrefix("UN"),
+		WithEnvironment(env),
+		WithResult(&result),
+	)
+	require.Nil(t, err)
+
+	require.Equal(t, []string{"fixtures/basic.env"}, result.Files)
+	require.Equal(t, []string{"fixtures/missing.env", "fixtures/missing.local.env"}, result.Skipped)
+	require.Equal(t, map[string]string{"UNEXPORTED": "data", "UNQUOTED": "unquoted data"}, env.Map())
+}
+
+func TestLoadOptionalSuffix(t *testing.T) {
+	os.Clearenv()
+
+	err := Load("fixtures/missing.env?", "fixtures/basic.env")
+	require.Nil(t, err)
+	require.Equal(t, "data", os.Getenv("EXPORTED"))
+
+	err = Load("fixtures/missing.env", "fixtures/basic.env?")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestLoadWithReader(t *testing.T) {
+	t.Parallel()
+
+	env := NewMapEnv(nil)
+
+	var result LoadResult
+	err := LoadWith(
+		WithFiles("fixtures/basic.env"),
+		WithReader(strings.NewReader("EXPORTED=reader\nFROM_READER=\"${EXPORTED}\""), "inline"),
+		WithOverride(),
+		WithKeyPattern("EXPORTED", "FROM_READER"),
+		WithEnvironment(env),
+		WithResult(&result),
+	)
+	require.Nil(t, err)
+
+	require.Equal(t, map[string]string{"EXPORTED": "reader", "FROM_READER": "reader"}, env.Map())
+	require.Equal(t, []string{"fixtures/basic.env", "inline"}, result.Files)
+}
+
+func TestLoadWithReaderInvalid(t *testing.T) {
+	t.Parallel()
+
+	err := LoadWith(
+		WithReader(strings.NewReader("A=1\njust some words"), "inline"),
+		WithStrict(),
+		WithEnvironment(NewMapEnv(nil)),
+	)
+	require.EqualError(t, err, `inline:2:6: unexpected IDENT "some", expected EQUALS`)
+}
+
+func TestLoadWithInvalidKeyPattern(t *testing.T) {
+	env := NewMapEnv(nil)
+
+	err := LoadWith(WithFiles("fixtures/prefix.env"), WithKeyPattern("BILLING_["), WithEnvironment(env))
+	require.ErrorIs(t, err, path.ErrBadPattern)
+	require.Empty(t, env.Keys())
+}
+
+func TestReadWith(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("USER", "app")
+	os.Setenv("DB_HOST", "existing")
+
+	envars, err := ReadWith(
+		WithFiles("fixtures/prefix.env"),
+		WithPrefix("BILLING_"),
+		WithStripPrefix("BILLING_"),
+		WithEnvironment(NewMapEnv(nil)),
+	)
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{
+		"DB_HOST": "db.local",
+		"DB_URL":  "postgres://db.local/billing",
+		"DB_USER": "app",
+	}, envars)
+	require.ElementsMatch(t, []string{"USER=app", "DB_HOST=existing"}, os.Environ())
+}
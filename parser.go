@@ -1,16 +1,106 @@
 package dotenv
 
 import (
+	"errors"
 	"fmt"
 )
 
 type Parser struct {
-	lex *lexer
+	lex  *lexer
+	name string
 }
+
+// QuoteStyle describes how the value of an entry was quoted in the source
+type QuoteStyle int
+
+const (
+	// QuoteNone is an unquoted value
+	QuoteNone QuoteStyle = iota
+	// QuoteSingle is a '' or triple single quoted value
+	QuoteSingle
+	// QuoteDouble is a "" or triple double quoted value
+	QuoteDouble
+	// QuoteBacktick is a `` quoted value
+	QuoteBacktick
+	// QuoteHeredoc is a <<EOF heredoc value
+	QuoteHeredoc
+)
+
+// String returns the name of the quote style
+func (q QuoteStyle) String() string {
+	switch q {
+	case QuoteSingle:
+		return "single"
+	case QuoteDouble:
+		return "double"
+	case QuoteBacktick:
+		return "backtick"
+	case QuoteHeredoc:
+		return "heredoc"
+	default:
+		return "none"
+	}
+}
+
 type ParseEntry struct {
 	Key   string
 	Value string
 	Raw   bool
+
+	// File is the path of the file the entry was found in, it will be empty if the source was
+	// not a file
+	File string
+	// Line is the 1-based line number of the entries key
+	Line int
+	// Column is the 1-based column of the entries key
+	Column int
+	// Quote is the quoting style used for the value
+	Quote QuoteStyle
+	// Exported is set if the entry was declared with the export keyword
+	Exported bool
+	// HasValue distinguishes between an empty value (KEY="") and an absent one (KEY=)
+	HasValue bool
+
+	// LeadingComments holds the comment lines directly above the entry, a blank line will break
+	// the block
+	LeadingComments []string
+	// InlineComment is the comment trailing the entry on the same line
+	InlineComment string
+
+	// masked holds the byte offsets of the escaped $ in a double quoted value
+	masked []int
+	// source holds the value as written in the file when it differs from the decoded value
+	source string
+}
+
+// commentBlock collects the comment only lines that directly precede an entry
+type commentBlock struct {
+	lines   []string
+	ownLine bool
+}
+
+// add records a comment found at the start of a line
+func (c *commentBlock) add(tkn token) {
+	c.lines = append(c.lines, tkn.Literal)
+	c.ownLine = true
+}
+
+// eol should be called at the end of every line, anything other than a comment only line will
+// break the block
+func (c *commentBlock) eol() {
+	if !c.ownLine {
+		c.lines = nil
+	}
+
+	c.ownLine = false
+}
+
+// take returns the collected comment lines and resets the block
+func (c *commentBlock) take() []string {
+	lines := c.lines
+	c.lines = nil
+
+	return lines
 }
 
 func newParser(l *lexer) *Parser {
@@ -19,79 +109,295 @@ func newParser(l *lexer) *Parser {
 	}
 }
 
+// ParseWarning describes a line, or part of a line, that was skipped by the lenient parser
+type ParseWarning struct {
+	// File is the path of the file being parsed, it will be empty if the source was not a file
+	File string
+	// Line is the 1-based line number of the skipped token
+	Line int
+	// Column is the 1-based column of the skipped token
+	Column int
+	// Reason describes why the token was skipped
+	Reason string
+}
+
+// String formats the warning as file:line:column: reason
+func (w ParseWarning) String() string {
+	if w.File == "" {
+		return fmt.Sprintf("%d:%d: %s", w.Line, w.Column, w.Reason)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s", w.File, w.Line, w.Column, w.Reason)
+}
+
+// Parse parses the envars from the source skipping over any lines that contain invalid syntax
 func (p *Parser) Parse() []ParseEntry {
-	var pairs []ParseEntry
+	pairs, _ := p.ParseWithWarnings()
+
+	return pairs
+}
+
+// ParseWithWarnings parses the envars from the source in the same way as Parse but also returns a
+// warning for each line that contained syntax that had to be skipped
+//
+// Only the first problem found on each line is reported
+func (p *Parser) ParseWithWarnings() ([]ParseEntry, []ParseWarning) {
+	var (
+		pairs    []ParseEntry
+		warnings []ParseWarning
+		warned   bool
+		exported bool
+		added    bool
+		prevType string
+		comments commentBlock
+	)
 
 	prev := make([]*token, 2)
 
+	warn := func(tkn token, reason string) {
+		if warned {
+			return
+		}
+
+		warned = true
+		warnings = append(warnings, ParseWarning{
+			File:   p.name,
+			Line:   tkn.Line + 1,
+			Column: tkn.Pos,
+			Reason: reason,
+		})
+	}
+
 	for {
 		tkn := p.lex.NextToken()
-		if tkn.Type == tknEOF {
-			break
-		}
+		atLineStart := prevType == "" || prevType == tknEOL
+		justAdded := false
 
 		switch tkn.Type {
 		case tknIdentifier:
+			if prev[0] != nil {
+				warn(*prev[0], fmt.Sprintf("missing = after key %q", prev[0].Literal))
+			}
+
 			prev[0] = &tkn
 			prev[1] = nil
+			exported = prevType == tknExport
 		case tknEquals:
 			if prev[0] != nil {
 				prev[1] = &tkn
+			} else {
+				warn(tkn, "missing key before =")
 			}
-		case tknValue, tknRawValue, tknComment, tknEOL, tknEOF:
+		case tknValue, tknRawValue, tknBacktickValue, tknComment, tknEOL, tknEOF:
 			if prev[0] != nil && prev[1] != nil {
-				if tkn.Type == tknValue || tkn.Type == tknRawValue {
-					pairs = append(pairs, ParseEntry{prev[0].Literal, tkn.Literal, tkn.Type == tknRawValue})
-				} else {
-					pairs = append(pairs, ParseEntry{prev[0].Literal, "", false})
-				}
+				entry := p.entry(*prev[0], tkn, exported)
+				entry.LeadingComments = comments.take()
+				pairs = append(pairs, entry)
+				justAdded = isValueToken(tkn)
+			} else if tkn.Type == tknComment && atLineStart {
+				comments.add(tkn)
+			} else if tkn.Type == tknComment && added {
+				pairs[len(pairs)-1].InlineComment = tkn.Literal
+			} else if prev[0] != nil {
+				warn(*prev[0], fmt.Sprintf("missing = after key %q", prev[0].Literal))
+			} else if isValueToken(tkn) {
+				warn(tkn, "value without a key")
 			}
+
+			if tkn.Type == tknEOL {
+				warned = false
+				comments.eol()
+			}
+
 			fallthrough
 		default:
+			if tkn.Type == tknIllegal {
+				reason := tkn.Reason
+				if reason == "" {
+					reason = "invalid syntax"
+				}
+
+				if tkn.Literal == "" {
+					warn(tkn, reason)
+				} else {
+					warn(tkn, fmt.Sprintf("%s %q", reason, tkn.Literal))
+				}
+			}
+
 			prev[0] = nil
 			prev[1] = nil
 		}
-	}
 
-	return pairs
+		if tkn.Type == tknEOF {
+			return pairs, warnings
+		}
+
+		prevType = tkn.Type
+		added = justAdded
+	}
 }
 
+// ParseStrict parses the envars from the source failing on the first invalid token
+//
+// the returned error will be a *SyntaxError describing the position of the invalid token
 func (p *Parser) ParseStrict() ([]ParseEntry, error) {
-	var pairs []ParseEntry
+	pairs, errs := p.parseStrict(false)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return pairs, nil
+}
+
+// ParseRecover parses the envars from the source with the same rules as ParseStrict but rather
+// than failing on the first invalid token it will skip to the next line and continue parsing
+//
+// All of the valid envars are returned along with an error joining a *SyntaxError for every
+// invalid line, the error can be inspected with errors.As or by unwrapping it
+func (p *Parser) ParseRecover() ([]ParseEntry, error) {
+	pairs, errs := p.parseStrict(true)
+
+	return pairs, errors.Join(errs...)
+}
+
+// parseStrict parses the source line by line
+//
+// if recovery is set then the parser will resynchronise at the next EOL after an error otherwise
+// it stops at the first error
+func (p *Parser) parseStrict(recovery bool) ([]ParseEntry, []error) {
+	var (
+		pairs    []ParseEntry
+		errs     []error
+		prevType string
+		comments commentBlock
+	)
 
-loop:
 	for {
 		tkn := p.lex.NextToken()
 		switch tkn.Type {
 		case tknEOF:
-			break loop
-		case tknComment, tknEOL:
-			// NB: these are all allowed on their own line and will be skipped
-		case tknExport:
-			tkn = p.lex.NextToken()
-			if tkn.Type != tknIdentifier {
-				return nil, fmt.Errorf("Unexpected token %s", tkn)
-			}
-			fallthrough
-		case tknIdentifier:
-			eqTkn := p.lex.NextToken()
-			if eqTkn.Type != tknEquals {
-				return nil, fmt.Errorf("Unexpected token %s", eqTkn)
+			return pairs, errs
+		case tknComment:
+			// NB: a comment that does not start its line can only follow a complete entry
+			if prevType == "" || prevType == tknEOL {
+				comments.add(tkn)
+			} else if len(pairs) > 0 {
+				pairs[len(pairs)-1].InlineComment = tkn.Literal
 			}
 
-			valTkn := p.lex.NextToken()
-			switch valTkn.Type {
-			case tknValue, tknRawValue:
-				pairs = append(pairs, ParseEntry{tkn.Literal, valTkn.Literal, valTkn.Type == tknRawValue})
-			case tknComment, tknEOL, tknEOF:
-				pairs = append(pairs, ParseEntry{tkn.Literal, "", false})
-			default:
-				return nil, fmt.Errorf("Unexpected token %s", valTkn)
-			}
-		default:
-			return nil, fmt.Errorf("Unexpected token %s", tkn)
+			prevType = tkn.Type
+			continue
+		case tknEOL:
+			comments.eol()
+
+			prevType = tkn.Type
+			continue
+		}
+
+		entry, last, err := p.parseStatement(tkn)
+		prevType = last.Type
+
+		if err == nil {
+			entry.LeadingComments = comments.take()
+			pairs = append(pairs, entry)
+			continue
+		}
+
+		comments.take()
+
+		errs = append(errs, err)
+		if !recovery {
+			return pairs, errs
+		}
+
+		for last.Type != tknEOL && last.Type != tknEOF {
+			last = p.lex.NextToken()
 		}
+
+		prevType = last.Type
 	}
+}
 
-	return pairs, nil
+// parseStatement parses a single assignment starting at tkn
+//
+// the last token consumed is returned, if the statement is invalid this will be the offending token
+func (p *Parser) parseStatement(tkn token) (ParseEntry, token, *SyntaxError) {
+	exported := tkn.Type == tknExport
+
+	switch tkn.Type {
+	case tknExport:
+		tkn = p.lex.NextToken()
+		if tkn.Type != tknIdentifier {
+			return ParseEntry{}, tkn, p.syntaxError(tkn, tknIdentifier)
+		}
+	case tknIdentifier:
+	default:
+		return ParseEntry{}, tkn, p.syntaxError(tkn, tknIdentifier, tknExport, tknComment, tknEOL)
+	}
+
+	eqTkn := p.lex.NextToken()
+	if eqTkn.Type != tknEquals {
+		return ParseEntry{}, eqTkn, p.syntaxError(eqTkn, tknEquals)
+	}
+
+	valTkn := p.lex.NextToken()
+	switch valTkn.Type {
+	case tknValue, tknRawValue, tknBacktickValue, tknComment, tknEOL, tknEOF:
+		return p.entry(tkn, valTkn, exported), valTkn, nil
+	default:
+		return ParseEntry{}, valTkn, p.syntaxError(valTkn, tknValue, tknRawValue, tknBacktickValue, tknEOL)
+	}
+}
+
+// entry builds a ParseEntry from the key token and the token that followed its =
+//
+// if the token following the = is not a value token then the entry is given an absent value
+func (p *Parser) entry(key, val token, exported bool) ParseEntry {
+	entry := ParseEntry{
+		Key:      key.Literal,
+		File:     p.name,
+		Line:     key.Line + 1,
+		Column:   key.Pos,
+		Exported: exported,
+	}
+
+	if val.Type == tknComment {
+		entry.InlineComment = val.Literal
+	}
+
+	if isValueToken(val) {
+		entry.Value = val.Literal
+		entry.Raw = val.Type == tknRawValue
+		entry.Quote = val.Quote
+		entry.HasValue = true
+		entry.masked = val.Masked
+		entry.source = val.Source
+	}
+
+	return entry
+}
+
+// syntaxError builds a SyntaxError describing the unexpected token
+//
+// if the lexer gave a reason for the token being invalid then it is used in place of expected
+func (p *Parser) syntaxError(tkn token, expected ...string) *SyntaxError {
+	if tkn.Reason != "" {
+		expected = nil
+	}
+
+	return &SyntaxError{
+		File:     p.name,
+		Line:     tkn.Line + 1,
+		Column:   tkn.Pos,
+		Token:    tkn.Type,
+		Literal:  tkn.Literal,
+		Expected: expected,
+		Reason:   tkn.Reason,
+		Source:   p.lex.sourceLine(tkn.Line),
+	}
+}
+
+// isValueToken reports whether the token holds the value side of an assignment
+func isValueToken(tkn token) bool {
+	return tkn.Type == tknValue || tkn.Type == tknRawValue || tkn.Type == tknBacktickValue
 }
@@ -15,36 +15,258 @@ var parseTestCases = []struct {
 	{
 		"fixtures/basic.env",
 		[]ParseEntry{
-			{Key: "EXPORTED", Value: "data", Raw: false},
-			{Key: "UNEXPORTED", Value: "data", Raw: false},
-			{Key: "SINGLE_QUOTE", Value: "single quote", Raw: true},
-			{Key: "DOUBLE_QUOTE", Value: "double quote", Raw: false},
-			{Key: "UNQUOTED", Value: "unquoted data", Raw: false},
-			{Key: "WITH_COMMENT", Value: "some data", Raw: false},
-			{Key: "HASH_WITH_COMMENT", Value: "some#data", Raw: false},
-			{Key: "MULTI_LINE", Value: "this\none has multiple\nlines", Raw: false},
+			{
+				Key: "EXPORTED", Value: "data", Raw: false,
+				File: "fixtures/basic.env", Line: 1, Column: 8, Exported: true, HasValue: true,
+			},
+			{
+				Key: "UNEXPORTED", Value: "data", Raw: false,
+				File: "fixtures/basic.env", Line: 2, Column: 1, HasValue: true,
+			},
+			{
+				Key: "SINGLE_QUOTE", Value: "single quote", Raw: true,
+				File: "fixtures/basic.env", Line: 3, Column: 1, Quote: QuoteSingle, HasValue: true,
+			},
+			{
+				Key: "DOUBLE_QUOTE", Value: "double quote", Raw: false,
+				File: "fixtures/basic.env", Line: 4, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "UNQUOTED", Value: "unquoted data", Raw: false,
+				File: "fixtures/basic.env", Line: 5, Column: 1, HasValue: true,
+			},
+			{
+				Key: "WITH_COMMENT", Value: "some data", Raw: false,
+				File: "fixtures/basic.env", Line: 6, Column: 1, HasValue: true,
+				InlineComment: "with a comment",
+			},
+			{
+				Key: "HASH_WITH_COMMENT", Value: "some#data", Raw: false,
+				File: "fixtures/basic.env", Line: 7, Column: 1, HasValue: true,
+				InlineComment: "with a comment",
+			},
+			{
+				Key: "MULTI_LINE", Value: "this\none has multiple\nlines", Raw: false,
+				File: "fixtures/basic.env", Line: 8, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
 		},
 	},
 	{
 		"fixtures/broken.env",
 		[]ParseEntry{
-			{Key: "EXPORTED", Value: "exported data", Raw: false},
-			{Key: "EMPTY", Value: "", Raw: false},
-			{Key: "EMPTY_WITH_COMMENT", Value: "", Raw: false},
-			{Key: "FINAL", Value: "valid", Raw: false},
+			{
+				Key: "EXPORTED", Value: "exported data", Raw: false,
+				File: "fixtures/broken.env", Line: 3, Column: 8, Exported: true, HasValue: true,
+				LeadingComments: []string{"comment"},
+			},
+			{
+				Key: "EMPTY", Value: "", Raw: false,
+				File: "fixtures/broken.env", Line: 4, Column: 1,
+			},
+			{
+				Key: "EMPTY_WITH_COMMENT", Value: "", Raw: false,
+				File: "fixtures/broken.env", Line: 6, Column: 1,
+				InlineComment: "data",
+			},
+			{
+				Key: "FINAL", Value: "valid", Raw: false,
+				File: "fixtures/broken.env", Line: 7, Column: 1, HasValue: true,
+			},
 		},
 	},
 	{
 		"fixtures/replacement.env",
 		[]ParseEntry{
-			{Key: "VALUE", Value: "inserted", Raw: false},
-			{Key: "REPLACE", Value: "${VALUE}", Raw: false},
-			{Key: "REPLACE_SINGLE", Value: "${VALUE}", Raw: true},
-			{Key: "REPLACE_DOUBLE", Value: "${VALUE}", Raw: false},
-			{Key: "REPLACE_PARTIAL", Value: "partialy ${VALUE} value", Raw: false},
-			{Key: "REPLACE_ESCAPED", Value: "partialy \\${VALUE} value", Raw: false},
-			{Key: "REPLACE_FROM_BASIC", Value: "${HASH_WITH_COMMENT}", Raw: false},
-			{Key: "REPLACE_FROM_BROKEN", Value: "${EMPTY}", Raw: false},
+			{
+				Key: "VALUE", Value: "inserted", Raw: false,
+				File: "fixtures/replacement.env", Line: 1, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "REPLACE", Value: "${VALUE}", Raw: false,
+				File: "fixtures/replacement.env", Line: 2, Column: 1, HasValue: true,
+			},
+			{
+				Key: "REPLACE_SINGLE", Value: "${VALUE}", Raw: true,
+				File: "fixtures/replacement.env", Line: 3, Column: 1, Quote: QuoteSingle, HasValue: true,
+			},
+			{
+				Key: "REPLACE_DOUBLE", Value: "${VALUE}", Raw: false,
+				File: "fixtures/replacement.env", Line: 4, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "REPLACE_PARTIAL", Value: "partialy ${VALUE} value", Raw: false,
+				File: "fixtures/replacement.env", Line: 5, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "REPLACE_ESCAPED", Value: "partialy ${VALUE} value", Raw: false,
+				File: "fixtures/replacement.env", Line: 6, Column: 1, Quote: QuoteDouble, HasValue: true,
+				masked: []int{9},
+				source: "partialy \\${VALUE} value",
+			},
+			{
+				Key: "REPLACE_FROM_BASIC", Value: "${HASH_WITH_COMMENT}", Raw: false,
+				File: "fixtures/replacement.env", Line: 7, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "REPLACE_FROM_BROKEN", Value: "${EMPTY}", Raw: false,
+				File: "fixtures/replacement.env", Line: 8, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+		},
+	},
+	{
+		"fixtures/escapes.env",
+		[]ParseEntry{
+			{
+				Key: "NEWLINE", Value: "line1\nline2", Raw: false,
+				File: "fixtures/escapes.env", Line: 1, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "line1\\nline2",
+			},
+			{
+				Key: "TAB", Value: "col1\tcol2", Raw: false,
+				File: "fixtures/escapes.env", Line: 2, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "col1\\tcol2",
+			},
+			{
+				Key: "QUOTES", Value: "say \"hi\" and 'bye'", Raw: false,
+				File: "fixtures/escapes.env", Line: 3, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "say \\\"hi\\\" and \\'bye\\'",
+			},
+			{
+				Key: "BACKSLASH", Value: "C:\\path", Raw: false,
+				File: "fixtures/escapes.env", Line: 4, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "C:\\\\path",
+			},
+			{
+				Key: "DOLLAR", Value: "${VALUE}", Raw: false,
+				File: "fixtures/escapes.env", Line: 5, Column: 1, Quote: QuoteDouble, HasValue: true,
+				masked: []int{0},
+				source: "\\${VALUE}",
+			},
+			{
+				Key: "UNICODE", Value: "café 😀", Raw: false,
+				File: "fixtures/escapes.env", Line: 6, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "caf\\u00e9 \\U0001F600",
+			},
+			{
+				Key: "HEX", Value: "AB", Raw: false,
+				File: "fixtures/escapes.env", Line: 7, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "\\x41\\x42",
+			},
+			{
+				Key: "RAW", Value: "line1\\nline2", Raw: true,
+				File: "fixtures/escapes.env", Line: 8, Column: 1, Quote: QuoteSingle, HasValue: true,
+			},
+			{
+				Key: "RAW_QUOTE", Value: "it's", Raw: true,
+				File: "fixtures/escapes.env", Line: 9, Column: 1, Quote: QuoteSingle, HasValue: true,
+				source: "it\\'s",
+			},
+		},
+	},
+	{
+		"fixtures/multiline.env",
+		[]ParseEntry{
+			{
+				Key: "TRIPLE_DOUBLE", Value: "first ${VALUE}\n\tsecond\tline", Raw: false,
+				File: "fixtures/multiline.env", Line: 1, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "first ${VALUE}\n\tsecond\\tline",
+			},
+			{
+				Key: "TRIPLE_SINGLE", Value: "raw ${VALUE}\\n", Raw: true,
+				File: "fixtures/multiline.env", Line: 5, Column: 1, Quote: QuoteSingle, HasValue: true,
+			},
+			{
+				Key: "INLINE_TRIPLE", Value: "one \"quoted\" line", Raw: false,
+				File: "fixtures/multiline.env", Line: 8, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "HEREDOC", Value: "SELECT *\n  FROM table;", Raw: false,
+				File: "fixtures/multiline.env", Line: 9, Column: 1, Quote: QuoteHeredoc, HasValue: true,
+			},
+			{
+				Key: "HEREDOC_RAW", Value: "${VALUE}", Raw: true,
+				File: "fixtures/multiline.env", Line: 13, Column: 1, Quote: QuoteHeredoc, HasValue: true,
+			},
+			{
+				Key: "HEREDOC_INDENTED", Value: "-----BEGIN KEY-----\n  abc\n-----END KEY-----", Raw: false,
+				File: "fixtures/multiline.env", Line: 16, Column: 1, Quote: QuoteHeredoc, HasValue: true,
+			},
+			{
+				Key: "AFTER", Value: "value", Raw: false,
+				File: "fixtures/multiline.env", Line: 21, Column: 1, HasValue: true,
+			},
+		},
+	},
+	{
+		"fixtures/continuation.env",
+		[]ParseEntry{
+			{
+				Key: "HOSTS", Value: "one.example.com,two.example.com,three.example.com", Raw: false,
+				File: "fixtures/continuation.env", Line: 1, Column: 1, HasValue: true,
+				InlineComment: "hosts",
+				source:        "one.example.com,\\\n    two.example.com,\\\n\tthree.example.com",
+			},
+			{
+				Key: "CRLF", Value: "first second", Raw: false,
+				File: "fixtures/continuation.env", Line: 4, Column: 1, HasValue: true,
+				source: "first \\\r\n  second",
+			},
+			{
+				Key: "QUOTED", Value: "quoted continuation", Raw: false,
+				File: "fixtures/continuation.env", Line: 6, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "quoted \\\ncontinuation",
+			},
+			{
+				Key: "LAST", Value: "no trailing newline", Raw: false,
+				File: "fixtures/continuation.env", Line: 8, Column: 1, HasValue: true,
+			},
+		},
+	},
+	{
+		"fixtures/backtick.env",
+		[]ParseEntry{
+			{
+				Key: "VALUE", Value: "inserted", Raw: false,
+				File: "fixtures/backtick.env", Line: 1, Column: 1, HasValue: true,
+			},
+			{
+				Key: "BACKTICK", Value: "it's \"mixed\"", Raw: false,
+				File: "fixtures/backtick.env", Line: 2, Column: 1, Quote: QuoteBacktick, HasValue: true,
+			},
+			{
+				Key: "BACKTICK_EXPAND", Value: "${VALUE}\\n", Raw: false,
+				File: "fixtures/backtick.env", Line: 3, Column: 1, Quote: QuoteBacktick, HasValue: true,
+			},
+			{
+				Key: "BACKTICK_ESCAPED", Value: "\\${VALUE} ` tick", Raw: false,
+				File: "fixtures/backtick.env", Line: 4, Column: 1, Quote: QuoteBacktick, HasValue: true,
+				source: "\\${VALUE} \\` tick",
+			},
+		},
+	},
+	{
+		"fixtures/comments.env",
+		[]ParseEntry{
+			{
+				Key: "DB_URL", Value: "postgres://localhost", Raw: false,
+				File: "fixtures/comments.env", Line: 3, Column: 1, HasValue: true,
+				LeadingComments: []string{"Database connection string", "used by the api"},
+				InlineComment:   "primary",
+			},
+			{
+				Key: "PORT", Value: "8080", Raw: false,
+				File: "fixtures/comments.env", Line: 7, Column: 1, HasValue: true,
+			},
+			{
+				Key: "HOST", Value: "localhost", Raw: false,
+				File: "fixtures/comments.env", Line: 10, Column: 8, Quote: QuoteDouble, Exported: true,
+				HasValue: true, LeadingComments: []string{"directly above"}, InlineComment: "inline",
+			},
+			{
+				Key: "EMPTY", Value: "", Raw: false,
+				File: "fixtures/comments.env", Line: 11, Column: 1,
+				InlineComment: "absent value",
+			},
 		},
 	},
 }
@@ -56,6 +278,7 @@ func TestParse(t *testing.T) {
 			require.Nil(t, err, "failed to load fixture: %s", err)
 
 			p := newParser(newLexer(string(data)))
+			p.name = tc.file
 			require.Equal(t, tc.expected, p.Parse())
 		})
 	}
@@ -69,33 +292,241 @@ var parseStrictTestCases = []struct {
 	{
 		"fixtures/basic.env",
 		[]ParseEntry{
-			{Key: "EXPORTED", Value: "data", Raw: false},
-			{Key: "UNEXPORTED", Value: "data", Raw: false},
-			{Key: "SINGLE_QUOTE", Value: "single quote", Raw: true},
-			{Key: "DOUBLE_QUOTE", Value: "double quote", Raw: false},
-			{Key: "UNQUOTED", Value: "unquoted data", Raw: false},
-			{Key: "WITH_COMMENT", Value: "some data", Raw: false},
-			{Key: "HASH_WITH_COMMENT", Value: "some#data", Raw: false},
-			{Key: "MULTI_LINE", Value: "this\none has multiple\nlines", Raw: false},
+			{
+				Key: "EXPORTED", Value: "data", Raw: false,
+				File: "fixtures/basic.env", Line: 1, Column: 8, Exported: true, HasValue: true,
+			},
+			{
+				Key: "UNEXPORTED", Value: "data", Raw: false,
+				File: "fixtures/basic.env", Line: 2, Column: 1, HasValue: true,
+			},
+			{
+				Key: "SINGLE_QUOTE", Value: "single quote", Raw: true,
+				File: "fixtures/basic.env", Line: 3, Column: 1, Quote: QuoteSingle, HasValue: true,
+			},
+			{
+				Key: "DOUBLE_QUOTE", Value: "double quote", Raw: false,
+				File: "fixtures/basic.env", Line: 4, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "UNQUOTED", Value: "unquoted data", Raw: false,
+				File: "fixtures/basic.env", Line: 5, Column: 1, HasValue: true,
+			},
+			{
+				Key: "WITH_COMMENT", Value: "some data", Raw: false,
+				File: "fixtures/basic.env", Line: 6, Column: 1, HasValue: true,
+				InlineComment: "with a comment",
+			},
+			{
+				Key: "HASH_WITH_COMMENT", Value: "some#data", Raw: false,
+				File: "fixtures/basic.env", Line: 7, Column: 1, HasValue: true,
+				InlineComment: "with a comment",
+			},
+			{
+				Key: "MULTI_LINE", Value: "this\none has multiple\nlines", Raw: false,
+				File: "fixtures/basic.env", Line: 8, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
 		},
 		nil,
 	},
 	{
 		"fixtures/broken.env",
 		nil,
-		errors.New("Unexpected token IDENT value=some line=0 pos=6"),
+		&SyntaxError{
+			File:     "fixtures/broken.env",
+			Line:     1,
+			Column:   6,
+			Token:    tknIdentifier,
+			Literal:  "some",
+			Expected: []string{tknEquals},
+			Source:   "just some words",
+		},
 	},
 	{
 		"fixtures/replacement.env",
 		[]ParseEntry{
-			{Key: "VALUE", Value: "inserted", Raw: false},
-			{Key: "REPLACE", Value: "${VALUE}", Raw: false},
-			{Key: "REPLACE_SINGLE", Value: "${VALUE}", Raw: true},
-			{Key: "REPLACE_DOUBLE", Value: "${VALUE}", Raw: false},
-			{Key: "REPLACE_PARTIAL", Value: "partialy ${VALUE} value", Raw: false},
-			{Key: "REPLACE_ESCAPED", Value: "partialy \\${VALUE} value", Raw: false},
-			{Key: "REPLACE_FROM_BASIC", Value: "${HASH_WITH_COMMENT}", Raw: false},
-			{Key: "REPLACE_FROM_BROKEN", Value: "${EMPTY}", Raw: false},
+			{
+				Key: "VALUE", Value: "inserted", Raw: false,
+				File: "fixtures/replacement.env", Line: 1, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "REPLACE", Value: "${VALUE}", Raw: false,
+				File: "fixtures/replacement.env", Line: 2, Column: 1, HasValue: true,
+			},
+			{
+				Key: "REPLACE_SINGLE", Value: "${VALUE}", Raw: true,
+				File: "fixtures/replacement.env", Line: 3, Column: 1, Quote: QuoteSingle, HasValue: true,
+			},
+			{
+				Key: "REPLACE_DOUBLE", Value: "${VALUE}", Raw: false,
+				File: "fixtures/replacement.env", Line: 4, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "REPLACE_PARTIAL", Value: "partialy ${VALUE} value", Raw: false,
+				File: "fixtures/replacement.env", Line: 5, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "REPLACE_ESCAPED", Value: "partialy ${VALUE} value", Raw: false,
+				File: "fixtures/replacement.env", Line: 6, Column: 1, Quote: QuoteDouble, HasValue: true,
+				masked: []int{9},
+				source: "partialy \\${VALUE} value",
+			},
+			{
+				Key: "REPLACE_FROM_BASIC", Value: "${HASH_WITH_COMMENT}", Raw: false,
+				File: "fixtures/replacement.env", Line: 7, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "REPLACE_FROM_BROKEN", Value: "${EMPTY}", Raw: false,
+				File: "fixtures/replacement.env", Line: 8, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+		},
+		nil,
+	},
+	{
+		"fixtures/escapes.env",
+		[]ParseEntry{
+			{
+				Key: "NEWLINE", Value: "line1\nline2", Raw: false,
+				File: "fixtures/escapes.env", Line: 1, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "line1\\nline2",
+			},
+			{
+				Key: "TAB", Value: "col1\tcol2", Raw: false,
+				File: "fixtures/escapes.env", Line: 2, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "col1\\tcol2",
+			},
+			{
+				Key: "QUOTES", Value: "say \"hi\" and 'bye'", Raw: false,
+				File: "fixtures/escapes.env", Line: 3, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "say \\\"hi\\\" and \\'bye\\'",
+			},
+			{
+				Key: "BACKSLASH", Value: "C:\\path", Raw: false,
+				File: "fixtures/escapes.env", Line: 4, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "C:\\\\path",
+			},
+			{
+				Key: "DOLLAR", Value: "${VALUE}", Raw: false,
+				File: "fixtures/escapes.env", Line: 5, Column: 1, Quote: QuoteDouble, HasValue: true,
+				masked: []int{0},
+				source: "\\${VALUE}",
+			},
+			{
+				Key: "UNICODE", Value: "café 😀", Raw: false,
+				File: "fixtures/escapes.env", Line: 6, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "caf\\u00e9 \\U0001F600",
+			},
+			{
+				Key: "HEX", Value: "AB", Raw: false,
+				File: "fixtures/escapes.env", Line: 7, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "\\x41\\x42",
+			},
+			{
+				Key: "RAW", Value: "line1\\nline2", Raw: true,
+				File: "fixtures/escapes.env", Line: 8, Column: 1, Quote: QuoteSingle, HasValue: true,
+			},
+			{
+				Key: "RAW_QUOTE", Value: "it's", Raw: true,
+				File: "fixtures/escapes.env", Line: 9, Column: 1, Quote: QuoteSingle, HasValue: true,
+				source: "it\\'s",
+			},
+		},
+		nil,
+	},
+	{
+		"fixtures/invalid_escape.env",
+		nil,
+		&SyntaxError{
+			File:    "fixtures/invalid_escape.env",
+			Line:    2,
+			Column:  14,
+			Token:   tknIllegal,
+			Literal: "\\q",
+			Reason:  "invalid escape sequence",
+			Source:  `INVALID="bad \q escape"`,
+		},
+	},
+	{
+		"fixtures/multiline.env",
+		[]ParseEntry{
+			{
+				Key: "TRIPLE_DOUBLE", Value: "first ${VALUE}\n\tsecond\tline", Raw: false,
+				File: "fixtures/multiline.env", Line: 1, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "first ${VALUE}\n\tsecond\\tline",
+			},
+			{
+				Key: "TRIPLE_SINGLE", Value: "raw ${VALUE}\\n", Raw: true,
+				File: "fixtures/multiline.env", Line: 5, Column: 1, Quote: QuoteSingle, HasValue: true,
+			},
+			{
+				Key: "INLINE_TRIPLE", Value: "one \"quoted\" line", Raw: false,
+				File: "fixtures/multiline.env", Line: 8, Column: 1, Quote: QuoteDouble, HasValue: true,
+			},
+			{
+				Key: "HEREDOC", Value: "SELECT *\n  FROM table;", Raw: false,
+				File: "fixtures/multiline.env", Line: 9, Column: 1, Quote: QuoteHeredoc, HasValue: true,
+			},
+			{
+				Key: "HEREDOC_RAW", Value: "${VALUE}", Raw: true,
+				File: "fixtures/multiline.env", Line: 13, Column: 1, Quote: QuoteHeredoc, HasValue: true,
+			},
+			{
+				Key: "HEREDOC_INDENTED", Value: "-----BEGIN KEY-----\n  abc\n-----END KEY-----", Raw: false,
+				File: "fixtures/multiline.env", Line: 16, Column: 1, Quote: QuoteHeredoc, HasValue: true,
+			},
+			{
+				Key: "AFTER", Value: "value", Raw: false,
+				File: "fixtures/multiline.env", Line: 21, Column: 1, HasValue: true,
+			},
+		},
+		nil,
+	},
+	{
+		"fixtures/continuation.env",
+		[]ParseEntry{
+			{
+				Key: "HOSTS", Value: "one.example.com,two.example.com,three.example.com", Raw: false,
+				File: "fixtures/continuation.env", Line: 1, Column: 1, HasValue: true,
+				InlineComment: "hosts",
+				source:        "one.example.com,\\\n    two.example.com,\\\n\tthree.example.com",
+			},
+			{
+				Key: "CRLF", Value: "first second", Raw: false,
+				File: "fixtures/continuation.env", Line: 4, Column: 1, HasValue: true,
+				source: "first \\\r\n  second",
+			},
+			{
+				Key: "QUOTED", Value: "quoted continuation", Raw: false,
+				File: "fixtures/continuation.env", Line: 6, Column: 1, Quote: QuoteDouble, HasValue: true,
+				source: "quoted \\\ncontinuation",
+			},
+			{
+				Key: "LAST", Value: "no trailing newline", Raw: false,
+				File: "fixtures/continuation.env", Line: 8, Column: 1, HasValue: true,
+			},
+		},
+		nil,
+	},
+	{
+		"fixtures/backtick.env",
+		[]ParseEntry{
+			{
+				Key: "VALUE", Value: "inserted", Raw: false,
+				File: "fixtures/backtick.env", Line: 1, Column: 1, HasValue: true,
+			},
+			{
+				Key: "BACKTICK", Value: "it's \"mixed\"", Raw: false,
+				File: "fixtures/backtick.env", Line: 2, Column: 1, Quote: QuoteBacktick, HasValue: true,
+			},
+			{
+				Key: "BACKTICK_EXPAND", Value: "${VALUE}\\n", Raw: false,
+				File: "fixtures/backtick.env", Line: 3, Column: 1, Quote: QuoteBacktick, HasValue: true,
+			},
+			{
+				Key: "BACKTICK_ESCAPED", Value: "\\${VALUE} ` tick", Raw: false,
+				File: "fixtures/backtick.env", Line: 4, Column: 1, Quote: QuoteBacktick, HasValue: true,
+				source: "\\${VALUE} \\` tick",
+			},
 		},
 		nil,
 	},
@@ -108,6 +539,7 @@ func TestParseStrict(t *testing.T) {
 			require.Nil(t, err, "failed to load fixture: %s", err)
 
 			p := newParser(newLexer(string(data)))
+			p.name = tc.file
 			pairs, err := p.ParseStrict()
 			if tc.expectedError != nil {
 				require.Equal(t, tc.expectedError, err)
@@ -119,3 +551,166 @@ func TestParseStrict(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRecover(t *testing.T) {
+	data, err := os.ReadFile("fixtures/broken.env")
+	require.Nil(t, err, "failed to load fixture: %s", err)
+
+	p := newParser(newLexer(string(data)))
+	p.name = "fixtures/broken.env"
+	pairs, err := p.ParseRecover()
+
+	require.Equal(t, []ParseEntry{
+		{
+			Key: "EXPORTED", Value: "exported data", Raw: false,
+			File: "fixtures/broken.env", Line: 3, Column: 8, Exported: true, HasValue: true,
+			LeadingComments: []string{"comment"},
+		},
+		{
+			Key: "EMPTY", Value: "", Raw: false,
+			File: "fixtures/broken.env", Line: 4, Column: 1,
+		},
+		{
+			Key: "EMPTY_WITH_COMMENT", Value: "", Raw: false,
+			File: "fixtures/broken.env", Line: 6, Column: 1,
+			InlineComment: "data",
+		},
+		{
+			Key: "FINAL", Value: "valid", Raw: false,
+			File: "fixtures/broken.env", Line: 7, Column: 1, HasValue: true,
+		},
+	}, pairs)
+	require.Equal(t, errors.Join(
+		&SyntaxError{
+			File:     "fixtures/broken.env",
+			Line:     1,
+			Column:   6,
+			Token:    tknIdentifier,
+			Literal:  "some",
+			Expected: []string{tknEquals},
+			Source:   "just some words",
+		},
+		&SyntaxError{
+			File:     "fixtures/broken.env",
+			Line:     5,
+			Column:   1,
+			Token:    tknEquals,
+			Literal:  "=",
+			Expected: []string{tknIdentifier, tknExport, tknComment, tknEOL},
+			Source:   "='single quote'",
+		},
+	), err)
+}
+
+func TestParseRecoverValid(t *testing.T) {
+	data, err := os.ReadFile("fixtures/basic.env")
+	require.Nil(t, err, "failed to load fixture: %s", err)
+
+	p := newParser(newLexer(string(data)))
+	p.name = "fixtures/basic.env"
+	pairs, err := p.ParseRecover()
+
+	require.Nil(t, err)
+	require.Equal(t, parseTestCases[0].expected, pairs)
+}
+
+var parseWithWarningsTestCases = []struct {
+	file     string
+	expected []ParseWarning
+}{
+	{
+		"fixtures/basic.env",
+		nil,
+	},
+	{
+		"fixtures/broken.env",
+		[]ParseWarning{
+			{File: "fixtures/broken.env", Line: 1, Column: 1, Reason: `missing = after key "just"`},
+			{File: "fixtures/broken.env", Line: 5, Column: 1, Reason: "missing key before ="},
+		},
+	},
+	{
+		"fixtures/invalid_escape.env",
+		[]ParseWarning{
+			{File: "fixtures/invalid_escape.env", Line: 2, Column: 14, Reason: `invalid escape sequence "\\q"`},
+		},
+	},
+}
+
+func TestParseWithWarnings(t *testing.T) {
+	for _, tc := range parseWithWarningsTestCases {
+		t.Run(tc.file, func(t *testing.T) {
+			data, err := os.ReadFile(tc.file)
+			require.Nil(t, err, "failed to load fixture: %s", err)
+
+			p := newParser(newLexer(string(data)))
+			p.name = tc.file
+			_, warnings := p.ParseWithWarnings()
+			require.Equal(t, tc.expected, warnings)
+		})
+	}
+}
+
+func TestParseWarningString(t *testing.T) {
+	w := ParseWarning{Line: 3, Column: 1, Reason: "missing key before ="}
+	require.Equal(t, "3:1: missing key before =", w.String())
+
+	w.File = ".env"
+	require.Equal(t, ".env:3:1: missing key before =", w.String())
+}
+
+func TestParseEntryHasValue(t *testing.T) {
+	p, err := ParseFile("fixtures/empty.env")
+	require.Nil(t, err)
+
+	pairs, err := p.ParseStrict()
+	require.Nil(t, err)
+
+	hasValue := make(map[string]bool)
+	for _, entry := range pairs {
+		require.Equal(t, "", entry.Value)
+		hasValue[entry.Key] = entry.HasValue
+	}
+
+	require.Equal(t, map[string]bool{
+		"ABSENT":              false,
+		"ABSENT_WITH_COMMENT": false,
+		"EMPTY_DOUBLE":        true,
+		"EMPTY_SINGLE":        true,
+	}, hasValue)
+}
+
+func TestParseEntryEscapes(t *testing.T) {
+	testCases := []struct {
+		data     string
+		expected string
+	}{
+		{`A="a\\b"`, `a\b`},
+		{`A="\$HOME"`, `$HOME`},
+		{`A="\\$HOME"`, `\$HOME`},
+		{`A="\x24HOME"`, `$HOME`},
+		{`A=a\\b`, `a\\b`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.data, func(t *testing.T) {
+			pairs, err := ParseString(testCase.data).ParseStrict()
+			require.Nil(t, err)
+			require.Len(t, pairs, 1)
+			require.Equal(t, testCase.expected, pairs[0].Value)
+		})
+	}
+}
+
+func TestParseUnterminatedHeredoc(t *testing.T) {
+	pairs, warnings := ParseString("HEREDOC=<<EOF\nNEXT=1", "inline").ParseWithWarnings()
+
+	require.Equal(t, []ParseWarning{
+		{File: "inline", Line: 1, Column: 9, Reason: "unterminated heredoc"},
+	}, warnings)
+	require.Len(t, pairs, 1)
+	require.Equal(t, "NEXT", pairs[0].Key)
+
+	_, err := ParseString("HEREDOC=<<EOF\nNEXT=1\nBAD LINE", "inline").ParseRecover()
+	require.EqualError(t, err, "inline:1:9: unterminated heredoc\ninline:3:5: unexpected IDENT \"LINE\", expected EQUALS")
+}
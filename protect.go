@@ -0,0 +1,88 @@
+package dotenv
+
+import (
+	"path"
+	"slices"
+)
+
+// DefaultProtectedKeys are the glob patterns of the keys protected by WithProtectedKeys when no
+// patterns are provided
+//
+// They cover variables that change how the process, its children or the go runtime behave
+var DefaultProtectedKeys = []string{
+	"PATH",
+	"HOME",
+	"USER",
+	"SHELL",
+	"IFS",
+	"ENV",
+	"BASH_ENV",
+	"PROMPT_COMMAND",
+	"LD_*",
+	"DYLD_*",
+	"GODEBUG",
+	"GOFLAGS",
+	"GOENV",
+	"GOROOT",
+	"GOPATH",
+	"GOTOOLCHAIN",
+	"GOMAXPROCS",
+	"GOGC",
+	"GOMEMLIMIT",
+	"GOTRACEBACK",
+	"NODE_OPTIONS",
+	"PYTHONPATH",
+	"PERL5OPT",
+}
+
+// ProtectAction controls what happens when a .env file attempts to set a protected key
+type ProtectAction int
+
+const (
+	// ProtectSkip skips the protected key and continues loading
+	ProtectSkip ProtectAction = iota
+	// ProtectError stops the load and returns a *ProtectedKeyError
+	ProtectError
+)
+
+// WithProtectedKeys prevents keys matching any of the glob patterns from being set, if no patterns
+// are provided then DefaultProtectedKeys will be used.
+//
+// Keys are checked after they have been rewritten and only if they would be set, an existing key
+// that is not being overridden is skipped as normal. Any protected keys found are recorded in the
+// LoadResult
+func WithProtectedKeys(action ProtectAction, patterns ...string) Option {
+	if len(patterns) == 0 {
+		patterns = DefaultProtectedKeys
+	}
+
+	return func(o *options) {
+		o.protect = true
+		o.protectAction = action
+		o.protected = append(o.protected, patterns...)
+	}
+}
+
+// WithAllowedKeys exempts keys matching any of the glob patterns from WithProtectedKeys
+func WithAllowedKeys(patterns ...string) Option {
+	return func(o *options) {
+		o.allowed = append(o.allowed, patterns...)
+	}
+}
+
+// isProtected reports whether the policy prevents key from being set
+func (o *options) isProtected(key string) bool {
+	if !o.protect {
+		return false
+	}
+
+	return matchAny(o.protected, key) && !matchAny(o.allowed, key)
+}
+
+// matchAny reports whether key matches any of the glob patterns
+func matchAny(patterns []string, key string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		matched, _ := path.Match(pattern, key)
+		return matched
+	})
+}
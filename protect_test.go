@@ -0,0 +1,100 @@
+package dotenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var protectTestCases = []struct {
+	name              string
+	opts              []Option
+	expected          map[string]string
+	expectedProtected []string
+	expectedError     error
+}{
+	{
+		name:     "disabled",
+		opts:     []Option{},
+		expected: map[string]string{"APP_NAME": "demo", "PATH": "/tmp/evil", "LD_PRELOAD": "/tmp/evil.so", "GODEBUG": "madvdontneed=1"},
+	},
+	{
+		name:              "skip defaults",
+		opts:              []Option{WithProtectedKeys(ProtectSkip)},
+		expected:          map[string]string{"APP_NAME": "demo"},
+		expectedProtected: []string{"PATH", "LD_PRELOAD", "GODEBUG"},
+	},
+	{
+		name:              "error",
+		opts:              []Option{WithProtectedKeys(ProtectError)},
+		expected:          map[string]string{"APP_NAME": "demo"},
+		expectedProtected: []string{"PATH"},
+		expectedError:     &ProtectedKeyError{File: "fixtures/protected.env", Line: 2, Column: 1, Key: "PATH"},
+	},
+	{
+		name:              "allowed",
+		opts:              []Option{WithProtectedKeys(ProtectSkip), WithAllowedKeys("GODEBUG")},
+		expected:          map[string]string{"APP_NAME": "demo", "GODEBUG": "madvdontneed=1"},
+		expectedProtected: []string{"PATH", "LD_PRELOAD"},
+	},
+	{
+		name:              "custom patterns",
+		opts:              []Option{WithProtectedKeys(ProtectSkip, "APP_*", "PATH")},
+		expected:          map[string]string{"LD_PRELOAD": "/tmp/evil.so", "GODEBUG": "madvdontneed=1"},
+		expectedProtected: []string{"APP_NAME", "PATH"},
+	},
+	{
+		name:              "rewritten keys",
+		opts:              []Option{WithProtectedKeys(ProtectSkip), WithKeyPattern("APP_*"), WithStripPrefix("APP_"), WithAddPrefix("LD_")},
+		expected:          map[string]string{},
+		expectedProtected: []string{"LD_NAME"},
+	},
+}
+
+func TestLoadWithProtectedKeys(t *testing.T) {
+	for _, tc := range protectTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var result LoadResult
+			env := NewMapEnv(nil)
+
+			err := LoadWith(append(tc.opts, WithFiles("fixtures/protected.env"), WithEnvironment(env), WithResult(&result))...)
+			require.Equal(t, tc.expectedError, err)
+
+			require.Equal(t, tc.expected, env.Map())
+			require.Equal(t, tc.expectedProtected, result.Protected)
+		})
+	}
+}
+
+func TestLoadAtomicProtectedKeys(t *testing.T) {
+	env := NewMapEnv(nil)
+
+	tx, err := loadAtomic(WithFiles("fixtures/protected.env"), WithEnvironment(env), WithProtectedKeys(ProtectError))
+	require.ErrorAs(t, err, new(*ProtectedKeyError))
+	require.Nil(t, tx)
+	require.Empty(t, env.Keys())
+}
+
+func TestLoadWithProtectedKeysExisting(t *testing.T) {
+	t.Parallel()
+
+	var result LoadResult
+	env := NewMapEnv(map[string]string{"PATH": "/usr/bin"})
+
+	err := LoadWith(
+		WithFiles("fixtures/protected.env"),
+		WithKeyPattern("PATH"),
+		WithProtectedKeys(ProtectError),
+		WithEnvironment(env),
+		WithResult(&result),
+	)
+	require.Nil(t, err)
+
+	require.Equal(t, map[string]string{"PATH": "/usr/bin"}, env.Map())
+	require.Empty(t, result.Protected)
+	require.Equal(t, []KeyResult{
+		{Key: "PATH", Outcome: KeySkipped, File: "fixtures/protected.env", Line: 2, OldValue: "/usr/bin", Existed: true, NewValue: "/tmp/evil"},
+	}, result.Keys)
+}
@@ -0,0 +1,159 @@
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Definition is a single definition of a key found while loading a .env file into the
+// os.environment
+type Definition struct {
+	// Key is the name of the variable after any rewriting
+	Key string
+	// File is the path of the file the key was found in
+	File string
+	// Line is the 1-based line number of the key
+	Line int
+	// Column is the 1-based column of the key
+	Column int
+	// Raw is the value as it was written in the file, without its quotes, before any escape
+	// sequences are decoded or variables expanded
+	Raw string
+	// Value is the expanded value
+	Value string
+	// Outcome is what happened to the definition
+	Outcome KeyOutcome
+	// Redacted is set if the values have been replaced with RedactedValue
+	Redacted bool
+}
+
+// String formats the definition as file:line: outcome "raw" => "value"
+func (d Definition) String() string {
+	return fmt.Sprintf("%s:%d: %s %q => %q", d.File, d.Line, d.Outcome, d.Raw, d.Value)
+}
+
+// Provenance describes where the value of a variable came from
+type Provenance struct {
+	// Key is the name of the variable
+	Key string
+	// Definitions lists every definition of the key in the order they were loaded, including those
+	// that were shadowed by an existing value
+	Definitions []Definition
+}
+
+// Winner returns the definition that set the current value of the variable
+//
+// If none of the definitions set the variable then false is returned, the value came from
+// outside of the loaded files
+func (p Provenance) Winner() (Definition, bool) {
+	if i := p.winner(); i >= 0 {
+		return p.Definitions[i], true
+	}
+
+	return Definition{}, false
+}
+
+// winner returns the index of the winning definition or -1 if there is none
+func (p Provenance) winner() int {
+	for i := len(p.Definitions) - 1; i >= 0; i-- {
+		switch p.Definitions[i].Outcome {
+		case KeySet, KeyOverridden:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// String explains the provenance of the variable, the winning definition is marked with a *
+func (p Provenance) String() string {
+	var buf strings.Builder
+
+	buf.WriteString(p.Key)
+
+	if len(p.Definitions) == 0 {
+		buf.WriteString(": not loaded from any file")
+		return buf.String()
+	}
+
+	winner := p.winner()
+	for i, def := range p.Definitions {
+		if i == winner {
+			buf.WriteString("\n* ")
+		} else {
+			buf.WriteString("\n  ")
+		}
+
+		buf.WriteString(def.String())
+	}
+
+	return buf.String()
+}
+
+// WithProvenance records every definition of the keys loaded into the os.environment so that they
+// can be explained with Explain, loads into any other Environment are not recorded
+//
+// The values of keys matching DefaultRedactedKeys or any patterns given to WithRedaction are
+// replaced with RedactedValue in the record
+func WithProvenance() Option {
+	return func(o *options) {
+		o.track = true
+	}
+}
+
+// Explain returns the provenance of a variable loaded into the os.environment by a load using
+// WithProvenance
+func Explain(key string) Provenance {
+	return registry.explain(key)
+}
+
+// ResetProvenance discards every definition recorded by WithProvenance
+func ResetProvenance() {
+	registry.reset()
+}
+
+// provenanceRegistry records the definitions of the keys loaded into the os.environment
+type provenanceRegistry struct {
+	mux  sync.RWMutex
+	keys map[string][]Definition
+}
+
+var registry = &provenanceRegistry{
+	keys: make(map[string][]Definition),
+}
+
+func (r *provenanceRegistry) add(defs []Definition) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for _, def := range defs {
+		r.keys[def.Key] = append(r.keys[def.Key], def)
+	}
+}
+
+func (r *provenanceRegistry) explain(key string) Provenance {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return Provenance{
+		Key:         key,
+		Definitions: append([]Definition(nil), r.keys[key]...),
+	}
+}
+
+func (r *provenanceRegistry) reset() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.keys = make(map[string][]Definition)
+}
+
+// publish adds the definitions tracked during the load to the registry
+func (o *options) publish() {
+	if len(o.defs) > 0 {
+		registry.add(o.defs)
+	}
+
+	o.defs = nil
+}
@@ -0,0 +1,147 @@
+package dotenv
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	os.Clearenv()
+	ResetProvenance()
+
+	fsys := fstest.MapFS{
+		".env":       &fstest.MapFile{Data: []byte("HOST=localhost\nAPI_URL=\"${HOST}/v1\"")},
+		".env.local": &fstest.MapFile{Data: []byte("API_URL=\"${HOST}/v2\"")},
+	}
+
+	err := LoadWith(WithFS(fsys), WithFiles(".env", ".env.local"), WithProvenance())
+	require.Nil(t, err)
+
+	provenance := Explain("API_URL")
+	require.Equal(t, Provenance{
+		Key: "API_URL",
+		Definitions: []Definition{
+			{Key: "API_URL", File: ".env", Line: 2, Column: 1, Raw: "${HOST}/v1", Value: "localhost/v1", Outcome: KeySet},
+			{Key: "API_URL", File: ".env.local", Line: 1, Column: 1, Raw: "${HOST}/v2", Value: "localhost/v2", Outcome: KeySkipped},
+		},
+	}, provenance)
+
+	winner, ok := provenance.Winner()
+	require.True(t, ok)
+	require.Equal(t, ".env", winner.File)
+
+	require.Equal(t, `API_URL
+* .env:2: set "${HOST}/v1" => "localhost/v1"
+  .env.local:1: skipped "${HOST}/v2" => "localhost/v2"`, provenance.String())
+
+	err = LoadWith(WithFS(fsys), WithFiles(".env.local"), WithOverride(), WithProvenance())
+	require.Nil(t, err)
+
+	winner, _ = Explain("API_URL").Winner()
+	require.Equal(t, Definition{
+		Key: "API_URL", File: ".env.local", Line: 1, Column: 1, Raw: "${HOST}/v2", Value: "localhost/v2", Outcome: KeyOverridden,
+	}, winner)
+}
+
+func TestExplainRawEscapes(t *testing.T) {
+	os.Clearenv()
+	ResetProvenance()
+
+	data := "HOME=/root\nESCAPED=\"\\${HOME}\"\nNEWLINE=\"a\\nb\"\nJOINED=first \\\n  second"
+
+	err := LoadWith(WithReader(strings.NewReader(data), "inline"), WithProvenance())
+	require.Nil(t, err)
+
+	winner, _ := Explain("ESCAPED").Winner()
+	require.Equal(t, "\\${HOME}", winner.Raw)
+	require.Equal(t, "${HOME}", winner.Value)
+	require.Equal(t, `inline:2: set "\\${HOME}" => "${HOME}"`, winner.String())
+
+	winner, _ = Explain("NEWLINE").Winner()
+	require.Equal(t, "a\\nb", winner.Raw)
+	require.Equal(t, "a\nb", winner.Value)
+
+	winner, _ = Explain("JOINED").Winner()
+	require.Equal(t, "first \\\n  second", winner.Raw)
+	require.Equal(t, "first second", winner.Value)
+}
+
+func TestExplainUnknown(t *testing.T) {
+	ResetProvenance()
+
+	provenance := Explain("UNKNOWN")
+	_, ok := provenance.Winner()
+	require.False(t, ok)
+	require.Equal(t, "UNKNOWN: not loaded from any file", provenance.String())
+}
+
+func TestExplainOptIn(t *testing.T) {
+	os.Clearenv()
+	ResetProvenance()
+
+	err := LoadWith(WithFiles("fixtures/basic.env"))
+	require.Nil(t, err)
+	require.Empty(t, Explain("EXPORTED").Definitions)
+
+	err = LoadReader(strings.NewReader("EXPORTED=reader"), "inline")
+	require.Nil(t, err)
+	require.Empty(t, Explain("EXPORTED").Definitions)
+
+	err = LoadWith(WithFiles("fixtures/basic.env"), WithProvenance())
+	require.Nil(t, err)
+	require.Len(t, Explain("EXPORTED").Definitions, 1)
+
+	ResetProvenance()
+	require.Empty(t, Explain("EXPORTED").Definitions)
+}
+
+func TestExplainOnlyTracksProcessEnvironment(t *testing.T) {
+	os.Clearenv()
+	ResetProvenance()
+
+	err := LoadWith(WithEnvironment(NewMapEnv(nil)), WithFiles("fixtures/basic.env"), WithProvenance())
+	require.Nil(t, err)
+	require.Empty(t, Explain("EXPORTED").Definitions)
+
+	_, err = ReadWith(WithFiles("fixtures/basic.env"), WithProvenance())
+	require.Nil(t, err)
+	require.Empty(t, Explain("EXPORTED").Definitions)
+}
+
+func TestExplainAtomic(t *testing.T) {
+	os.Clearenv()
+	ResetProvenance()
+
+	_, err := loadAtomic(WithFiles("fixtures/basic.env", "fixtures/broken.env"), WithProvenance())
+	require.NotNil(t, err)
+	require.Empty(t, Explain("EXPORTED").Definitions)
+
+	_, err = loadAtomic(WithFiles("fixtures/basic.env"), WithProvenance())
+	require.Nil(t, err)
+	require.Len(t, Explain("EXPORTED").Definitions, 1)
+}
+
+func TestExplainRedacted(t *testing.T) {
+	os.Clearenv()
+	ResetProvenance()
+
+	fsys := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte("DB_PASSWORD=hunter2\nEXPORTED=data")},
+	}
+
+	err := LoadWith(WithFS(fsys), WithRedaction("EXPORTED"), WithProvenance())
+	require.Nil(t, err)
+
+	require.Equal(t, []Definition{
+		{Key: "DB_PASSWORD", File: ".env", Line: 1, Column: 1, Raw: RedactedValue, Value: RedactedValue, Outcome: KeySet, Redacted: true},
+	}, Explain("DB_PASSWORD").Definitions)
+	require.Equal(t, []Definition{
+		{Key: "EXPORTED", File: ".env", Line: 2, Column: 1, Raw: RedactedValue, Value: RedactedValue, Outcome: KeySet, Redacted: true},
+	}, Explain("EXPORTED").Definitions)
+	require.Equal(t, "hunter2", os.Getenv("DB_PASSWORD"))
+	require.Equal(t, "data", os.Getenv("EXPORTED"))
+}
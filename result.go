@@ -0,0 +1,146 @@
+package dotenv
+
+// LoadResult describes the outcome of a load
+type LoadResult struct {
+	// Files lists the files that were loaded in the order they were loaded
+	Files []string
+	// Skipped lists the optional files that were skipped because they do not exist
+	Skipped []string
+	// Protected lists the protected keys that the files attempted to set
+	Protected []string
+	// Keys lists the outcome of every key found in the loaded files in the order they were found,
+	// keys removed by a key filter are not included
+	Keys []KeyResult
+	// Warnings lists the lines that were skipped because they contain invalid syntax, this is only
+	// filled by lenient loads as strict loads report them in the returned error
+	Warnings []ParseWarning
+}
+
+// KeyOutcome describes what happened to a key during a load
+type KeyOutcome int
+
+const (
+	// KeySet is a key that did not exist and was set
+	KeySet KeyOutcome = iota
+	// KeyOverridden is a key that already existed and was replaced
+	KeyOverridden
+	// KeySkipped is a key that already existed and was kept
+	KeySkipped
+	// KeyProtected is a key that was not set because it is protected
+	KeyProtected
+)
+
+// String returns the name of the outcome
+func (k KeyOutcome) String() string {
+	switch k {
+	case KeySet:
+		return "set"
+	case KeyOverridden:
+		return "overridden"
+	case KeySkipped:
+		return "skipped"
+	case KeyProtected:
+		return "protected"
+	default:
+		return "unknown"
+	}
+}
+
+// KeyResult describes the outcome of a single key during a load
+type KeyResult struct {
+	// Key is the name of the variable after any rewriting
+	Key string
+	// Outcome is what happened to the key
+	Outcome KeyOutcome
+	// File is the path of the file the key was found in
+	File string
+	// Line is the 1-based line number of the key
+	Line int
+	// OldValue is the value of the variable before the key was loaded
+	OldValue string
+	// Existed is set if the variable existed before the key was loaded
+	Existed bool
+	// NewValue is the expanded value of the key, for skipped and protected keys this is the value
+	// that would have been set
+	NewValue string
+	// Redacted is set if the values have been replaced by WithRedaction
+	Redacted bool
+}
+
+// RedactedValue replaces the values of redacted keys in a LoadResult
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedKeys are the glob patterns of the keys redacted by WithRedaction when no patterns
+// are provided
+var DefaultRedactedKeys = []string{
+	"*SECRET*",
+	"*PASSWORD*",
+	"*PASSWD*",
+	"*TOKEN*",
+	"*KEY*",
+	"*CREDENTIAL*",
+	"*PRIVATE*",
+	"*DSN*",
+}
+
+// WithResult fills result with the outcome of the load
+func WithResult(result *LoadResult) Option {
+	return func(o *options) {
+		o.result = result
+	}
+}
+
+// WithRedaction replaces the old and new values of keys matching any of the glob patterns with
+// RedactedValue in the LoadResult, if no patterns are provided then DefaultRedactedKeys will be used
+//
+// This only affects the result and the provenance registry, the values set in the environment are
+// unchanged
+func WithRedaction(patterns ...string) Option {
+	if len(patterns) == 0 {
+		patterns = DefaultRedactedKeys
+	}
+
+	return func(o *options) {
+		o.redacted = append(o.redacted, patterns...)
+	}
+}
+
+// record adds the outcome of a key to the result and tracks its definition for the provenance
+// registry
+func (o *options) record(res KeyResult, entry ParseEntry) {
+	if o.track {
+		def := Definition{
+			Key:     res.Key,
+			File:    res.File,
+			Line:    res.Line,
+			Column:  entry.Column,
+			Raw:     entry.written(),
+			Value:   res.NewValue,
+			Outcome: res.Outcome,
+		}
+
+		// NB: the registry outlives the load so the values of sensitive keys are always redacted
+		if matchAny(o.redacted, res.Key) || matchAny(DefaultRedactedKeys, res.Key) {
+			def.Raw = RedactedValue
+			def.Value = RedactedValue
+			def.Redacted = true
+		}
+
+		o.defs = append(o.defs, def)
+	}
+
+	if o.result == nil {
+		return
+	}
+
+	if matchAny(o.redacted, res.Key) {
+		res.Redacted = true
+		res.NewValue = RedactedValue
+
+		if res.Existed {
+			res.OldValue = RedactedValue
+		}
+	}
+
+	o.result.Keys = append(o.result.Keys, res)
+}
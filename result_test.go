@@ -0,0 +1,112 @@
+package dotenv
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+var resultFS = fstest.MapFS{
+	".env":       &fstest.MapFile{Data: []byte("A=1\nAPI_TOKEN=abc\nEXISTING=file")},
+	".env.local": &fstest.MapFile{Data: []byte("A=2\nB=${A}")},
+}
+
+var resultTestCases = []struct {
+	name     string
+	opts     []Option
+	expected []KeyResult
+}{
+	{
+		name: "load",
+		expected: []KeyResult{
+			{Key: "A", Outcome: KeySet, File: ".env", Line: 1, NewValue: "1"},
+			{Key: "API_TOKEN", Outcome: KeySet, File: ".env", Line: 2, NewValue: "abc"},
+			{Key: "EXISTING", Outcome: KeySkipped, File: ".env", Line: 3, OldValue: "env", Existed: true, NewValue: "file"},
+			{Key: "A", Outcome: KeySkipped, File: ".env.local", Line: 1, OldValue: "1", Existed: true, NewValue: "2"},
+			{Key: "B", Outcome: KeySet, File: ".env.local", Line: 2, NewValue: "1"},
+		},
+	},
+	{
+		name: "override",
+		opts: []Option{WithOverride()},
+		expected: []KeyResult{
+			{Key: "A", Outcome: KeySet, File: ".env", Line: 1, NewValue: "1"},
+			{Key: "API_TOKEN", Outcome: KeySet, File: ".env", Line: 2, NewValue: "abc"},
+			{Key: "EXISTING", Outcome: KeyOverridden, File: ".env", Line: 3, OldValue: "env", Existed: true, NewValue: "file"},
+			{Key: "A", Outcome: KeyOverridden, File: ".env.local", Line: 1, OldValue: "1", Existed: true, NewValue: "2"},
+			{Key: "B", Outcome: KeySet, File: ".env.local", Line: 2, NewValue: "2"},
+		},
+	},
+	{
+		name: "redaction",
+		opts: []Option{WithOverride(), WithRedaction(), WithRedaction("EXISTING")},
+		expected: []KeyResult{
+			{Key: "A", Outcome: KeySet, File: ".env", Line: 1, NewValue: "1"},
+			{Key: "API_TOKEN", Outcome: KeySet, File: ".env", Line: 2, NewValue: RedactedValue, Redacted: true},
+			{Key: "EXISTING", Outcome: KeyOverridden, File: ".env", Line: 3, OldValue: RedactedValue, Existed: true, NewValue: RedactedValue, Redacted: true},
+			{Key: "A", Outcome: KeyOverridden, File: ".env.local", Line: 1, OldValue: "1", Existed: true, NewValue: "2"},
+			{Key: "B", Outcome: KeySet, File: ".env.local", Line: 2, NewValue: "2"},
+		},
+	},
+	{
+		name: "protected and filtered",
+		opts: []Option{WithKeyPattern("A*"), WithProtectedKeys(ProtectSkip, "API_*")},
+		expected: []KeyResult{
+			{Key: "A", Outcome: KeySet, File: ".env", Line: 1, NewValue: "1"},
+			{Key: "API_TOKEN", Outcome: KeyProtected, File: ".env", Line: 2, NewValue: "abc"},
+			{Key: "A", Outcome: KeySkipped, File: ".env.local", Line: 1, OldValue: "1", Existed: true, NewValue: "2"},
+		},
+	},
+}
+
+func TestLoadResultKeys(t *testing.T) {
+	for _, tc := range resultTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var result LoadResult
+			env := NewMapEnv(map[string]string{"EXISTING": "env"})
+
+			err := LoadWith(append(tc.opts, WithFS(resultFS), WithFiles(".env", ".env.local"), WithEnvironment(env), WithResult(&result))...)
+			require.Nil(t, err)
+
+			require.Equal(t, tc.expected, result.Keys)
+		})
+	}
+}
+
+func TestLoadResultRedactionDoesNotChangeValues(t *testing.T) {
+	env := NewMapEnv(nil)
+
+	err := LoadWith(WithFS(resultFS), WithRedaction(), WithEnvironment(env), WithResult(&LoadResult{}))
+	require.Nil(t, err)
+
+	val, _ := env.Lookup("API_TOKEN")
+	require.Equal(t, "abc", val)
+}
+
+func TestLoadResultWarnings(t *testing.T) {
+	var result LoadResult
+
+	err := LoadWith(
+		WithFiles("fixtures/basic.env", "fixtures/broken.env"),
+		WithEnvironment(NewMapEnv(nil)),
+		WithResult(&result),
+	)
+	require.Nil(t, err)
+
+	require.Equal(t, []string{"fixtures/basic.env", "fixtures/broken.env"}, result.Files)
+	require.Equal(t, []ParseWarning{
+		{File: "fixtures/broken.env", Line: 1, Column: 1, Reason: `missing = after key "just"`},
+		{File: "fixtures/broken.env", Line: 5, Column: 1, Reason: "missing key before ="},
+	}, result.Warnings)
+}
+
+func TestKeyOutcomeString(t *testing.T) {
+	require.Equal(t, "set", KeySet.String())
+	require.Equal(t, "overridden", KeyOverridden.String())
+	require.Equal(t, "skipped", KeySkipped.String())
+	require.Equal(t, "protected", KeyProtected.String())
+	require.Equal(t, "unknown", KeyOutcome(-1).String())
+}
@@ -0,0 +1,127 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// SearchMode controls how files are looked for in the parent directories of the working directory
+type SearchMode int
+
+const (
+	// SearchNone only looks for files relative to the working directory
+	SearchNone SearchMode = iota
+	// SearchClosest walks up the parent directories and loads the first matching file found
+	SearchClosest
+	// SearchMerge walks up the parent directories and loads every matching file found, files
+	// closer to the working directory take precedence
+	SearchMerge
+)
+
+// searchBoundaries mark the root of a project, the search will not continue past a directory
+// containing one of them
+var searchBoundaries = []string{"go.mod", ".git"}
+
+// WithSearch looks for relative file paths in the parent directories of the working directory as
+// well as the working directory itself.
+//
+// The search stops at the first directory containing a go.mod file or .git directory, or at the
+// root of the file system. Absolute paths, readers and files read from an fs.FS are not searched
+// for.
+func WithSearch(mode SearchMode) Option {
+	return func(o *options) {
+		o.search = mode
+	}
+}
+
+// searchParents replaces each of the relative files with the matches found in parent directories,
+// files with no matches are kept as is so that they are handled by the load as missing files
+func (o *options) searchParents(files []envFile) []envFile {
+	if o.search == SearchNone || o.fsys != nil {
+		return files
+	}
+
+	dirs, err := searchDirs()
+	if err != nil {
+		return files
+	}
+
+	var found []envFile
+	for _, file := range files {
+		if file.reader != nil || filepath.IsAbs(file.path) {
+			found = append(found, file)
+			continue
+		}
+
+		matches := o.searchFile(dirs, file)
+		if len(matches) == 0 {
+			found = append(found, file)
+		}
+
+		found = append(found, matches...)
+	}
+
+	return found
+}
+
+// searchFile looks for file in each of dirs, the matches are returned in the order they should be
+// loaded for the file closest to the working directory to win
+func (o *options) searchFile(dirs []string, file envFile) []envFile {
+	var matches []envFile
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, file.path)
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+
+		matches = append(matches, envFile{path: path, optional: file.optional})
+
+		if o.search == SearchClosest {
+			break
+		}
+	}
+
+	if o.overwrite {
+		slices.Reverse(matches)
+	}
+
+	return matches
+}
+
+// searchDirs returns the relative paths of the working directory and each of its parents up to
+// the search boundary
+func searchDirs() ([]string, error) {
+	abs, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := []string{"."}
+	rel := "."
+
+	for !isSearchBoundary(abs) {
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			break
+		}
+
+		abs = parent
+		rel = filepath.Join(rel, "..")
+		dirs = append(dirs, rel)
+	}
+
+	return dirs, nil
+}
+
+// isSearchBoundary reports whether dir marks the root of a project
+func isSearchBoundary(dir string) bool {
+	for _, marker := range searchBoundaries {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
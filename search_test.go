@@ -0,0 +1,118 @@
+package dotenv
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// searchTree creates a project below an unrelated .env file and changes the working directory to
+// a nested package within it
+//
+//	.env
+//	project/go.mod
+//	project/.env
+//	project/service/.env
+//	project/service/pkg/
+func searchTree(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	files := map[string]string{
+		".env":                 "OUTSIDE=outside\nSHARED=outside",
+		"project/go.mod":       "module project",
+		"project/.env":         "PROJECT=project\nSHARED=project",
+		"project/service/.env": "SERVICE=service\nSHARED=service",
+	}
+
+	for path, data := range files {
+		path = filepath.Join(root, path)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, os.WriteFile(path, []byte(data), 0644))
+	}
+
+	require.Nil(t, os.MkdirAll(filepath.Join(root, "project/service/pkg"), 0755))
+	t.Chdir(filepath.Join(root, "project/service/pkg"))
+
+	return root
+}
+
+var searchTestCases = []struct {
+	name          string
+	opts          []Option
+	expectedFiles []string
+	expected      map[string]string
+}{
+	{
+		name:          "closest",
+		opts:          []Option{WithSearch(SearchClosest)},
+		expectedFiles: []string{"../.env"},
+		expected:      map[string]string{"SERVICE": "service", "SHARED": "service"},
+	},
+	{
+		name:          "merge",
+		opts:          []Option{WithSearch(SearchMerge)},
+		expectedFiles: []string{"../.env", "../../.env"},
+		expected:      map[string]string{"SERVICE": "service", "PROJECT": "project", "SHARED": "service"},
+	},
+	{
+		name:          "merge override",
+		opts:          []Option{WithSearch(SearchMerge), WithOverride()},
+		expectedFiles: []string{"../../.env", "../.env"},
+		expected:      map[string]string{"SERVICE": "service", "PROJECT": "project", "SHARED": "service"},
+	},
+	{
+		name:          "missing optional",
+		opts:          []Option{WithSearch(SearchMerge), WithOptionalFiles(".env.missing")},
+		expectedFiles: nil,
+		expected:      map[string]string{},
+	},
+}
+
+func TestLoadWithSearch(t *testing.T) {
+	for _, tc := range searchTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			searchTree(t)
+
+			var result LoadResult
+			env := NewMapEnv(nil)
+
+			err := LoadWith(append(tc.opts, WithEnvironment(env), WithResult(&result))...)
+			require.Nil(t, err)
+
+			require.Equal(t, tc.expectedFiles, result.Files)
+			require.Equal(t, tc.expected, env.Map())
+		})
+	}
+}
+
+func TestLoadWithSearchMissing(t *testing.T) {
+	searchTree(t)
+
+	err := LoadWith(WithSearch(SearchClosest), WithFiles(".env.missing"), WithEnvironment(NewMapEnv(nil)))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestLoadWithSearchAbsolute(t *testing.T) {
+	root := searchTree(t)
+
+	var result LoadResult
+	env := NewMapEnv(nil)
+	path := filepath.Join(root, ".env")
+
+	err := LoadWith(WithSearch(SearchMerge), WithFiles(path), WithEnvironment(env), WithResult(&result))
+	require.Nil(t, err)
+
+	require.Equal(t, []string{path}, result.Files)
+	require.Equal(t, map[string]string{"OUTSIDE": "outside", "SHARED": "outside"}, env.Map())
+}
+
+func TestLoadWithoutSearch(t *testing.T) {
+	searchTree(t)
+
+	err := LoadWith(WithEnvironment(NewMapEnv(nil)))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,81 @@
+package dotenv
+
+import "errors"
+
+// EnvSnapshot is a copy of the state of an Environment at a point in time
+type EnvSnapshot struct {
+	env    Environment
+	envars map[string]string
+}
+
+// Snapshot captures the current state of the os.environment
+func Snapshot() *EnvSnapshot {
+	return SnapshotEnv(OSEnv{})
+}
+
+// SnapshotEnv captures the current state of env
+func SnapshotEnv(env Environment) *EnvSnapshot {
+	snapshot := &EnvSnapshot{
+		env:    env,
+		envars: make(map[string]string),
+	}
+
+	for _, key := range env.Keys() {
+		if val, ok := env.Lookup(key); ok {
+			snapshot.envars[key] = val
+		}
+	}
+
+	return snapshot
+}
+
+// Restore puts the environment back into the exact state it was in when the snapshot was taken
+//
+// Variables added since the snapshot are unset and any that have been changed or unset are
+// restored, the snapshot can be restored any number of times
+func (s *EnvSnapshot) Restore() error {
+	var errs []error
+
+	for _, key := range s.env.Keys() {
+		if _, ok := s.envars[key]; !ok {
+			errs = append(errs, s.env.Unset(key))
+		}
+	}
+
+	for key, val := range s.envars {
+		if current, ok := s.env.Lookup(key); !ok || current != val {
+			errs = append(errs, s.env.Set(key, val))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// TB is the subset of testing.TB used by RestoreOnCleanup
+type TB interface {
+	Helper()
+	Cleanup(func())
+	Errorf(format string, args ...any)
+}
+
+// RestoreOnCleanup takes a snapshot of the os.environment and restores it when the test and all
+// of its subtests complete
+//
+//	func TestConfig(t *testing.T) {
+//		dotenv.RestoreOnCleanup(t)
+//
+//		err := dotenv.Overload(".env.test")
+//		...
+//	}
+func RestoreOnCleanup(t TB) *EnvSnapshot {
+	t.Helper()
+
+	snapshot := Snapshot()
+	t.Cleanup(func() {
+		if err := snapshot.Restore(); err != nil {
+			t.Errorf("dotenv: failed to restore environment: %s", err)
+		}
+	})
+
+	return snapshot
+}
@@ -0,0 +1,88 @@
+package dotenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("UNCHANGED", "value")
+	os.Setenv("CHANGED", "original")
+	os.Setenv("REMOVED", "original")
+	os.Setenv("EMPTY", "")
+
+	snapshot := Snapshot()
+
+	os.Setenv("CHANGED", "changed")
+	os.Unsetenv("REMOVED")
+	os.Unsetenv("EMPTY")
+	os.Setenv("ADDED", "added")
+	require.Nil(t, Overload("fixtures/basic.env"))
+
+	require.Nil(t, snapshot.Restore())
+	require.ElementsMatch(t, []string{"UNCHANGED=value", "CHANGED=original", "REMOVED=original", "EMPTY="}, os.Environ())
+
+	// the snapshot can be restored more than once
+	os.Setenv("CHANGED", "again")
+	require.Nil(t, snapshot.Restore())
+	require.Equal(t, "original", os.Getenv("CHANGED"))
+}
+
+func TestSnapshotEnv(t *testing.T) {
+	env := NewMapEnv(map[string]string{"EXISTING": "original"})
+	snapshot := SnapshotEnv(env)
+
+	require.Nil(t, OverloadEnv(env, "fixtures/basic.env"))
+	require.Nil(t, env.Set("EXISTING", "changed"))
+
+	require.Nil(t, snapshot.Restore())
+	require.Equal(t, map[string]string{"EXISTING": "original"}, env.Map())
+}
+
+// fakeTB records the cleanup functions and errors of a test
+type fakeTB struct {
+	cleanups []func()
+	errors   []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, format)
+}
+
+func TestRestoreOnCleanup(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("EXISTING", "original")
+
+	tb := &fakeTB{}
+	RestoreOnCleanup(tb)
+	require.Len(t, tb.cleanups, 1)
+
+	require.Nil(t, Overload("fixtures/basic.env"))
+	os.Setenv("EXISTING", "changed")
+
+	tb.cleanups[0]()
+	require.Empty(t, tb.errors)
+	require.ElementsMatch(t, []string{"EXISTING=original"}, os.Environ())
+}
+
+func TestRestoreOnCleanupWithTesting(t *testing.T) {
+	os.Clearenv()
+
+	t.Run("load", func(t *testing.T) {
+		RestoreOnCleanup(t)
+
+		require.Nil(t, Load("fixtures/basic.env"))
+		require.NotEmpty(t, os.Environ())
+	})
+
+	require.Empty(t, os.Environ())
+}
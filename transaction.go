@@ -0,0 +1,142 @@
+package dotenv
+
+import (
+	"errors"
+	"sync"
+)
+
+// Transaction records the changes made to an Environment by an atomic load so that they can be
+// rolled back
+type Transaction struct {
+	mux     sync.Mutex
+	env     Environment
+	changes []change
+}
+
+// change records the state of a variable before it was set
+type change struct {
+	key     string
+	prev    string
+	existed bool
+}
+
+// Keys returns the sorted names of the variables that were set by the load
+func (t *Transaction) Keys() []string {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	keys := make([]string, len(t.changes))
+	for i, c := range t.changes {
+		keys[i] = c.key
+	}
+
+	return keys
+}
+
+// Rollback restores every variable set by the load to its prior state, variables that did not
+// exist before the load will be unset
+//
+// Once rolled back the transaction is empty so further calls will do nothing
+func (t *Transaction) Rollback() error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	var errs []error
+
+	for i := len(t.changes) - 1; i >= 0; i-- {
+		c := t.changes[i]
+
+		if c.existed {
+			errs = append(errs, t.env.Set(c.key, c.prev))
+		} else {
+			errs = append(errs, t.env.Unset(c.key))
+		}
+	}
+
+	t.changes = nil
+
+	return errors.Join(errs...)
+}
+
+// LoadAtomic loads the provided list of .env files into the os.environment.
+// If no files are provided it will default to loading .env from the current working directory.
+//
+// Load operations will not replace any existing variables already in the environment.
+//
+// Atomic operations parse and expand every file before touching the environment, if any of the
+// files cannot be read or contain invalid syntax then nothing will be loaded. The returned
+// Transaction can be used to restore the environment to its state before the load.
+func LoadAtomic(filepaths ...string) (*Transaction, error) {
+	return loadAtomic(WithFiles(filepaths...))
+}
+
+// LoadAtomicEnv loads the provided list of .env files into env.
+// If no files are provided it will default to loading .env from the current working directory.
+//
+// This works the same way as LoadAtomic but allows the envars to be loaded into any Environment
+func LoadAtomicEnv(env Environment, filepaths ...string) (*Transaction, error) {
+	return loadAtomic(WithEnvironment(env), WithFiles(filepaths...))
+}
+
+// OverloadAtomic loads the provided list of .env files into the os.environment.
+// If no files are provided it will default to loading .env from the current working directory.
+//
+// Unlike with the LoadAtomic operation any existing environment variables will be overloaded with
+// the values present in the provided env files.
+//
+// Atomic operations parse and expand every file before touching the environment, if any of the
+// files cannot be read or contain invalid syntax then nothing will be loaded. The returned
+// Transaction can be used to restore the environment to its state before the load.
+func OverloadAtomic(filepaths ...string) (*Transaction, error) {
+	return loadAtomic(WithFiles(filepaths...), WithOverride())
+}
+
+// OverloadAtomicEnv loads the provided list of .env files into env.
+// If no files are provided it will default to loading .env from the current working directory.
+//
+// This works the same way as OverloadAtomic but allows the envars to be loaded into any
+// Environment
+func OverloadAtomicEnv(env Environment, filepaths ...string) (*Transaction, error) {
+	return loadAtomic(WithEnvironment(env), WithFiles(filepaths...), WithOverride())
+}
+
+func loadAtomic(opts ...Option) (*Transaction, error) {
+	o := newOptions(append(opts, WithStrict())...)
+	env := o.env
+
+	// NB: the load is staged in a layer above env so that lookups and expansion see the same
+	//     values they would during a normal load without env being modified
+	staged := NewMapEnv(nil)
+	o.env = NewLayeredEnv(staged, env)
+	o.resolve = o.env
+
+	if err := o.load(); err != nil {
+		return nil, err
+	}
+
+	tx, err := commit(env, staged)
+	if err == nil {
+		o.publish()
+	}
+
+	return tx, err
+}
+
+// commit applies the staged variables to env, if any of them fail to be set then the ones that
+// have already been applied are rolled back
+func commit(env Environment, staged *MapEnv) (*Transaction, error) {
+	tx := &Transaction{env: env}
+
+	for _, key := range staged.Keys() {
+		val, _ := staged.Lookup(key)
+		prev, existed := env.Lookup(key)
+
+		if err := env.Set(key, val); err != nil {
+			return nil, errors.Join(err, tx.Rollback())
+		}
+
+		tx.changes = append(tx.changes, change{key: key, prev: prev, existed: existed})
+	}
+
+	return tx, nil
+}
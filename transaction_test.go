@@ -0,0 +1,114 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverloadAtomic(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("EXPORTED", "original")
+	os.Setenv("UNRELATED", "untouched")
+
+	tx, err := OverloadAtomic("fixtures/basic.env")
+	require.Nil(t, err)
+	require.Equal(t, "data", os.Getenv("EXPORTED"))
+	require.Equal(t, []string{
+		"DOUBLE_QUOTE",
+		"EXPORTED",
+		"HASH_WITH_COMMENT",
+		"MULTI_LINE",
+		"SINGLE_QUOTE",
+		"UNEXPORTED",
+		"UNQUOTED",
+		"WITH_COMMENT",
+	}, tx.Keys())
+
+	require.Nil(t, tx.Rollback())
+	require.ElementsMatch(t, []string{"EXPORTED=original", "UNRELATED=untouched"}, os.Environ())
+	require.Empty(t, tx.Keys())
+
+	// a second rollback should not undo anything that has happened since
+	os.Setenv("EXPORTED", "changed")
+	require.Nil(t, tx.Rollback())
+	require.Equal(t, "changed", os.Getenv("EXPORTED"))
+}
+
+func TestLoadAtomic(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("EXPORTED", "original")
+
+	tx, err := LoadAtomic("fixtures/basic.env")
+	require.Nil(t, err)
+	require.Equal(t, "original", os.Getenv("EXPORTED"))
+	require.NotContains(t, tx.Keys(), "EXPORTED")
+
+	require.Nil(t, tx.Rollback())
+	require.ElementsMatch(t, []string{"EXPORTED=original"}, os.Environ())
+}
+
+func TestLoadAtomicInvalid(t *testing.T) {
+	for _, tc := range loadStrictTestCases {
+		if tc.expectedError == nil {
+			continue
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			os.Clearenv()
+
+			tx, err := LoadAtomic(tc.files...)
+			require.Equal(t, tc.expectedError, err)
+			require.Nil(t, tx)
+			require.Empty(t, os.Environ())
+		})
+	}
+}
+
+func TestLoadAtomicMissing(t *testing.T) {
+	env := NewMapEnv(nil)
+
+	tx, err := LoadAtomicEnv(env, "fixtures/basic.env", "fixtures/missing.env")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.Nil(t, tx)
+	require.Empty(t, env.Keys())
+}
+
+func TestOverloadAtomicEnvExpansion(t *testing.T) {
+	env := NewMapEnv(map[string]string{"VALUE": "original"})
+
+	// staged values from earlier files should be visible to expansion in later ones
+	tx, err := OverloadAtomicEnv(env, "fixtures/basic.env", "fixtures/replacement.env")
+	require.Nil(t, err)
+
+	val, _ := env.Lookup("REPLACE_FROM_BASIC")
+	require.Equal(t, "some#data", val)
+
+	require.Nil(t, tx.Rollback())
+	require.Equal(t, map[string]string{"VALUE": "original"}, env.Map())
+}
+
+// failingEnv fails to set the named key
+type failingEnv struct {
+	*MapEnv
+	key string
+}
+
+func (e failingEnv) Set(key, value string) error {
+	if key == e.key {
+		return errors.New("set failed")
+	}
+
+	return e.MapEnv.Set(key, value)
+}
+
+func TestLoadAtomicSetFailure(t *testing.T) {
+	env := failingEnv{MapEnv: NewMapEnv(map[string]string{"DOUBLE_QUOTE": "original"}), key: "SINGLE_QUOTE"}
+
+	tx, err := OverloadAtomicEnv(env, "fixtures/basic.env")
+	require.EqualError(t, err, "set failed")
+	require.Nil(t, tx)
+	require.Equal(t, map[string]string{"DOUBLE_QUOTE": "original"}, env.Map())
+}